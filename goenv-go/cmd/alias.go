@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage version aliases",
+	Long: `Manage version aliases.
+An alias is a name such as 'team' or 'lts' pointing at a Go version. Aliases
+are accepted anywhere a version is, e.g. 'goenv local team', and version
+files keep the alias name so that updating the alias updates every user.`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:     "set <name> <version>",
+	Short:   "Create or update an alias",
+	Args:    cobra.ExactArgs(2),
+	Example: "goenv alias set team 1.22",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		version, err := vm.SetAlias(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\n", args[0], version)
+
+		return nil
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"remove"},
+	Short:   "Remove an alias",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		if err := vm.RemoveAlias(args[0]); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var aliasLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List aliases",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		aliases, err := vm.Aliases()
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("%s -> %s\n", name, aliases[name])
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd, aliasRmCmd, aliasLsCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-nv/goenv/internal/cache"
+	"github.com/go-nv/goenv/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// shortHash abbreviates a SHA256 checksum for display.
+func shortHash(sha256 string) string {
+	if len(sha256) > 12 {
+		return sha256[:12]
+	}
+	return sha256
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the download cache",
+	Long: `Manage the cache of downloaded Go archives.
+Archives are stored by their SHA256 checksum and reused by 'goenv install'.
+Set GOENV_CACHE_DIR to share the cache between goenv roots.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached archives",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := cache.New(cfg.CacheDir)
+
+		entries, err := c.List()
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for _, entry := range entries {
+			fmt.Printf("  %-40s %10s  %s\n", entry.Filename, utils.FormatBytes(entry.Size), shortHash(entry.SHA256))
+			total += entry.Size
+		}
+		fmt.Printf("%d archive(s), %s total\n", len(entries), utils.FormatBytes(total))
+
+		return nil
+	},
+}
+
+// pruneOlderThan is the age of the cached archives removed by `cache prune`.
+var pruneOlderThan time.Duration
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached archives that have not been used recently",
+	Long: `Remove cached archives that have not been used recently.
+An archive counts as used when it is stored or installed from the cache.
+Archives are pruned by age rather than by the versions installed, as the
+cache may be shared with other goenv roots through GOENV_CACHE_DIR.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := cache.New(cfg.CacheDir)
+
+		cutoff := time.Now().Add(-pruneOlderThan)
+		removed, err := c.Prune(func(entry cache.Entry) bool {
+			return entry.ModTime.After(cutoff)
+		})
+		if err != nil {
+			return err
+		}
+
+		var freed int64
+		for _, entry := range removed {
+			fmt.Printf("Removed %s\n", entry.Filename)
+			freed += entry.Size
+		}
+		fmt.Printf("Freed %s\n", utils.FormatBytes(freed))
+
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached archives",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := cache.New(cfg.CacheDir)
+
+		size, err := c.Size()
+		if err != nil {
+			return err
+		}
+
+		if err := c.Clear(); err != nil {
+			return err
+		}
+		fmt.Printf("Freed %s\n", utils.FormatBytes(size))
+
+		return nil
+	},
+}
+
+var cachePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show the cache directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := cache.New(cfg.CacheDir)
+		fmt.Println(c.Dir())
+
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 30*24*time.Hour, "Remove archives not used for this long")
+	cacheCmd.AddCommand(cacheListCmd, cachePruneCmd, cacheClearCmd, cachePathCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
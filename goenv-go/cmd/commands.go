@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listShCommands   bool
+	listNoShCommands bool
+)
+
+var commandsCmd = &cobra.Command{
+	Use:   "commands [--sh | --no-sh]",
+	Short: "List all available commands of goenv",
+	Long: `List all available commands of goenv, including external goenv-<name>
+commands found under GOENV_ROOT/plugins/*/bin and in PATH.
+--sh lists only the commands evaluated by the goenv shell function and
+--no-sh all others.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var names []string
+		if !listShCommands {
+			for _, c := range rootCmd.Commands() {
+				if !c.Hidden && !strings.HasPrefix(c.Name(), plugins.ShPrefix) {
+					names = append(names, c.Name())
+				}
+			}
+		}
+		if !listNoShCommands {
+			names = append(names, shellFunctionCommands()...)
+		}
+
+		sort.Strings(names)
+		for i, name := range names {
+			if i == 0 || name != names[i-1] {
+				fmt.Println(name)
+			}
+		}
+
+		return nil
+	},
+}
+
+// shellFunctionCommands returns the commands whose sh- variant is evaluated
+// by the goenv shell function: the built-in ones and those of goenv-sh-<name>
+// plugins.
+func shellFunctionCommands() []string {
+	commands := append([]string{}, shCommands...)
+
+	pm, err := plugins.NewPluginManager(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return commands
+	}
+
+	for _, plugin := range pm.List() {
+		if name, ok := strings.CutPrefix(plugin.Name, plugins.ShPrefix); ok && !isBuiltinCommand(plugin.Name) {
+			commands = append(commands, name)
+		}
+	}
+
+	return commands
+}
+
+func init() {
+	commandsCmd.Flags().BoolVar(&listShCommands, "sh", false, "List only commands evaluated by the goenv shell function")
+	commandsCmd.Flags().BoolVar(&listNoShCommands, "no-sh", false, "List only commands not evaluated by the goenv shell function")
+	commandsCmd.MarkFlagsMutuallyExclusive("sh", "no-sh")
+	rootCmd.AddCommand(commandsCmd)
+}
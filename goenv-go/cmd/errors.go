@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/go-nv/goenv/internal/installer"
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/go-nv/goenv/internal/versions"
+)
+
+// Exit codes of goenv, documented in the root command's help so that
+// scripts can tell failures apart.
+const (
+	exitError            = 1   // Any other failure, including usage errors
+	exitNotInstalled     = 2   // The selected or requested version is not installed
+	exitNotFound         = 3   // The version is not available from the release index
+	exitChecksumMismatch = 4   // A downloaded archive failed verification
+	exitNetwork          = 5   // The release index or an archive could not be downloaded
+	exitPermission       = 6   // A file or directory could not be accessed
+	exitCommandNotFound  = 127 // The selected version does not provide the command
+)
+
+// exitCodesHelp describes the exit codes in the root command's help.
+const exitCodesHelp = `Exit codes:
+  1    any other failure, including usage errors
+  2    the selected or requested version is not installed
+  3    the version is not available from the release index
+  4    a downloaded archive failed checksum verification
+  5    network failure downloading the release index or an archive
+  6    permission denied
+  127  the selected version does not provide the command`
+
+// exitStatus is returned by commands that already reported the failure and
+// only need goenv to exit with a given code.
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	var status exitStatus
+	switch {
+	case errors.As(err, &status):
+		return int(status)
+	case errors.Is(err, installer.ErrChecksumMismatch):
+		return exitChecksumMismatch
+	case errors.Is(err, installer.ErrVersionNotFound):
+		return exitNotFound
+	case errors.Is(err, installer.ErrNetwork):
+		return exitNetwork
+	case errors.Is(err, versions.ErrNotInstalled):
+		return exitNotInstalled
+	case errors.Is(err, runner.ErrCommandNotFound):
+		return exitCommandNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return exitPermission
+	default:
+		return exitError
+	}
+}
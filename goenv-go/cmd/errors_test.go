@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/installer"
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/go-nv/goenv/internal/versions"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "general", err: errors.New("boom"), want: exitError},
+		{name: "exit status", err: exitStatus(exitError), want: exitError},
+		{name: "wrapped exit status", err: fmt.Errorf("shell: %w", exitStatus(42)), want: 42},
+		{name: "not installed", err: fmt.Errorf("version '1.22.0' is %w", versions.ErrNotInstalled), want: exitNotInstalled},
+		{name: "not found", err: fmt.Errorf("failed to install version: Go 1.99 %w", installer.ErrVersionNotFound), want: exitNotFound},
+		{name: "checksum mismatch", err: fmt.Errorf("failed to install version: %w", installer.ErrChecksumMismatch), want: exitChecksumMismatch},
+		{name: "network", err: fmt.Errorf("failed to fetch versions: %w", installer.ErrNetwork), want: exitNetwork},
+		{name: "permission", err: &fs.PathError{Op: "mkdir", Path: "/root/.goenv", Err: syscall.EACCES}, want: exitPermission},
+		{name: "command not found", err: fmt.Errorf("'gofmt' %w", runner.ErrCommandNotFound), want: exitCommandNotFound},
+		{
+			name: "checksum mismatch wins over network",
+			err:  errors.Join(fmt.Errorf("mirror: %w", installer.ErrNetwork), fmt.Errorf("go.dev: %w", installer.ErrChecksumMismatch)),
+			want: exitChecksumMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <command> [arg1 arg2...]",
+	Short: "Run an executable with the selected Go version",
+	Long: `Run an executable with the selected Go version.
+Prepares the environment so that the selected Go version's 'bin' directory
+is at the front of PATH, GOROOT points to the version (unless
+GOENV_DISABLE_GOROOT=1) and GOPATH to GOENV_GOPATH_PREFIX/<version> (unless
+GOENV_DISABLE_GOPATH=1), combined with an existing GOPATH when
+GOENV_APPEND_GOPATH or GOENV_PREPEND_GOPATH is set.`,
+	Example:            "goenv exec go build ./...",
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			cmd.Help()
+			return exitStatus(exitError)
+		}
+
+		r, err := runner.NewRunner(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Exec only returns on error
+		return r.Exec(args[0], args[1:], "")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+}
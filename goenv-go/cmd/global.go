@@ -14,29 +14,28 @@ var globalCmd = &cobra.Command{
 If no version is specified, the current global version will be shown.
 If a version is specified, it will be set as the global version.`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		vm, err := versions.NewVersionManager()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 
 		if len(args) == 0 {
 			version, err := vm.GetGlobalVersion()
 			if err != nil {
-				fmt.Println(err)
-				return
+				return err
 			}
 			fmt.Println(version)
-			return
+			return nil
 		}
 
-		err = vm.SetGlobalVersion(args[0])
+		version, err := vm.SetGlobalVersion(args[0])
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
-		fmt.Println("Global version set to", args[0])
+		fmt.Println("Global version set to", version)
+
+		return nil
 	},
 }
 
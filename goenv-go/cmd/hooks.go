@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/hooks"
+	"github.com/spf13/cobra"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks <event>",
+	Short: "List hook scripts for a given event",
+	Long: `List hook scripts for a given event.
+Hooks are looked up in the <event> subdirectory of each directory in
+GOENV_HOOK_PATH, then of GOENV_ROOT/goenv.d, /usr/local/etc/goenv.d,
+/etc/goenv.d, /usr/lib/goenv/hooks and the etc/goenv.d directory of every
+plugin.
+
+Events: ` + strings.Join(hooks.Events, ", ") + `.
+
+'.bash' hooks are sourced by bash like in the bash goenv; other executable
+files are run. Hooks get their context in GOENV_* environment variables and
+as a JSON object on stdin, and may change GOENV_VERSION (version-name),
+GOENV_VERSION_ORIGIN (version-origin), GOENV_COMMAND_PATH (which, exec) or
+any variable of the command's environment (exec). Executable hooks do so by
+printing 'KEY=value' or 'unset KEY' lines.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: hooks.Events,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hm, err := hooks.NewHookManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		for _, script := range hm.Scripts(args[0]) {
+			fmt.Println(script)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+}
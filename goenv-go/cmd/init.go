@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initShell    string
+	initNoRehash bool
+)
+
+// shCommands are the commands that change the environment of the calling
+// shell. The goenv shell function evaluates the output of their sh- variant.
+var shCommands = []string{"rehash", "shell"}
+
+var initCmd = &cobra.Command{
+	Use:   "init [-] [--shell <shell>] [--no-rehash]",
+	Short: "Configure the shell environment for goenv",
+	Long: `Configure the shell environment for goenv.
+Without '-', shows how to load goenv from your shell profile. With '-',
+prints the code to evaluate: it sets GOENV_ROOT, sources the goenv
+configuration file (GOENV_RC_FILE, ~/.goenvrc by default), adds the shims
+directory to PATH (at the end, or at the front when GOENV_PATH_ORDER=front),
+loads completions and defines the goenv shell function needed by
+'goenv shell' and 'goenv rehash'.
+
+The shell is detected from the parent process unless given with --shell.
+Supported shells are bash, zsh, ksh, fish, nu (nushell) and elvish.`,
+	Example:   `eval "$(goenv init -)"`,
+	Args:      cobra.MaximumNArgs(2),
+	ValidArgs: append([]string{"-"}, shell.Shells...),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printScript := false
+		sh := initShell
+		for _, arg := range args {
+			switch {
+			case arg == "-":
+				printScript = true
+			case sh == "":
+				// `goenv init - zsh`, as accepted by the bash goenv-init
+				sh = arg
+			}
+		}
+		if sh == "" {
+			sh = shell.Detect()
+		}
+
+		if !printScript {
+			home := os.Getenv("HOME")
+			_, rcErr := os.Stat(filepath.Join(home, ".bashrc"))
+			_, profileErr := os.Stat(filepath.Join(home, ".bash_profile"))
+			bashrc := rcErr == nil && os.IsNotExist(profileErr)
+			profile, code := shell.Profile(sh, bashrc)
+
+			fmt.Fprintf(os.Stderr, "# Load goenv automatically by appending\n# the following to %s:\n\n%s\n\n", profile, code)
+			return nil
+		}
+
+		for _, dir := range []string{filepath.Join(cfg.RootDir, constants.ShimsDir), cfg.VersionsDir()} {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+		}
+
+		script, err := shell.Init(shell.InitOptions{
+			Shell:    sh,
+			RootDir:  cfg.RootDir,
+			NoRehash: initNoRehash,
+			Commands: shellFunctionCommands(),
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(script)
+
+		return nil
+	},
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initShell, "shell", "", "Shell to generate code for (bash, zsh, ksh, fish, nu, elvish)")
+	initCmd.Flags().BoolVar(&initNoRehash, "no-rehash", false, "Do not rehash shims when the shell starts")
+	rootCmd.AddCommand(initCmd)
+}
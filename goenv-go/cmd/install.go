@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
 	"github.com/go-nv/goenv/internal/installer"
 	"github.com/go-nv/goenv/internal/versions"
 	"github.com/spf13/cobra"
@@ -12,10 +14,13 @@ var (
 	forceInstall bool
 	listVersions bool
 	skipExisting bool
+	skipChecksum bool
+	archivePath  string
+	archiveSHA   string
 )
 
 func listAvailableVersions(cmd *cobra.Command, args []string) error {
-	installer, err := installer.NewInstaller()
+	installer, err := installer.NewInstaller(cfg)
 	if err != nil {
 		return err
 	}
@@ -32,26 +37,107 @@ func listAvailableVersions(cmd *cobra.Command, args []string) error {
 }
 
 func installVersion(cmd *cobra.Command, args []string) error {
-	version := args[0]
+	vm, err := versions.NewVersionManager(cfg)
+	if err != nil {
+		return err
+	}
 
-	if !skipExisting {
-		vm, err := versions.NewVersionManager()
+	// Without a version, install the one selected for the current directory
+	var spec string
+	if len(args) > 0 {
+		spec = args[0]
+	} else {
+		resolution, err := vm.Resolve("")
 		if err != nil {
 			return err
 		}
+		if resolution.Version == constants.GoSystemVersion {
+			return fmt.Errorf("no version configured (set by %s)", resolution.Origin)
+		}
+		spec = resolution.Version
+	}
 
-		if vm.IsVersionInstalled(version) {
-			fmt.Printf("Go %s is already installed\n", version)
-			return nil
+	// Aliases, e.g. read from .go-version, name the version to install
+	if target, ok := vm.ResolveAlias(spec); ok {
+		spec = target
+	}
+	if spec == constants.GoSystemVersion {
+		return fmt.Errorf("the %s version cannot be installed", constants.GoSystemVersion)
+	}
+
+	// An installed exact version needs no release index, so that
+	// --skip-existing and auto-install work offline
+	if v, err := goversion.Parse(spec); err == nil && !v.IsPartial() && vm.IsVersionInstalled(v.String()) && !forceInstall {
+		if !skipExisting {
+			fmt.Printf("Go %s is already installed, use --force to reinstall\n", v)
 		}
+		return nil
 	}
 
-	installer, err := installer.NewInstaller()
+	installer, err := installer.NewInstaller(cfg, installer.WithSkipChecksum(skipChecksum))
 	if err != nil {
 		return err
 	}
 
-	if err := installer.Install(version); err != nil {
+	release, err := installer.Resolve(spec)
+	if err != nil {
+		return err
+	}
+
+	if vm.IsVersionInstalled(release.Version) && !forceInstall {
+		if !skipExisting {
+			fmt.Printf("Go %s is already installed, use --force to reinstall\n", release.Version)
+		}
+		return nil
+	}
+
+	if err := installer.Install(release); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully installed Go %s\n", release.Version)
+	return nil
+}
+
+func installArchive(cmd *cobra.Command, args []string) error {
+	version, err := installer.ArchiveVersion(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// A version next to --archive must agree with the archive's go/VERSION
+	if len(args) > 0 {
+		want, err := goversion.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		got, err := goversion.Parse(version)
+		if err != nil {
+			return err
+		}
+		if !got.Matches(want) {
+			return fmt.Errorf("archive contains Go %s, not %s", version, args[0])
+		}
+	}
+
+	vm, err := versions.NewVersionManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	if vm.IsVersionInstalled(version) && !forceInstall {
+		if !skipExisting {
+			fmt.Printf("Go %s is already installed, use --force to reinstall\n", version)
+		}
+		return nil
+	}
+
+	installer, err := installer.NewInstaller(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := installer.InstallArchive(archivePath, archiveSHA); err != nil {
 		return err
 	}
 
@@ -60,34 +146,54 @@ func installVersion(cmd *cobra.Command, args []string) error {
 }
 
 var installCmd = &cobra.Command{
-	Use:   "install <version>",
+	Use:   "install [<version> | --archive <file>]",
 	Short: "Install a specific version of Go",
 	Long: `Install a specific version of Go.
-The version should be in the format of X.Y.Z (e.g., 1.21.0).`,
-	Run: func(cmd *cobra.Command, args []string) {
+Without a version, the version selected for the current directory is
+installed. The version should be in the format of X.Y.Z (e.g., 1.21.0). A partial
+version such as X.Y (e.g., 1.22) installs the newest patch release, 'latest'
+installs the newest stable release and 'unstable' the newest release
+including betas and release candidates. A constraint such as '>=1.21 <1.23'
+installs the newest release satisfying it.
+
+Archives are downloaded from go.dev unless GOENV_MIRROR_URL lists other
+mirrors (http(s) URLs, file:// URLs or directories, comma-separated and tried
+in order). GOENV_INDEX_URL likewise overrides the release index. Both can also
+be set in the goenv configuration file (~/.goenvrc or GOENV_RC_FILE).
+
+Use --archive to install from a local archive, e.g. on hosts without internet
+access. The version is detected from the archive's go/VERSION file; a version
+given as well must match it. The archive must be built for the current OS and
+architecture.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if listVersions {
 			if err := listAvailableVersions(cmd, args); err != nil {
-				fmt.Println("Error listing available versions:", err)
+				return fmt.Errorf("failed to list available versions: %w", err)
 			}
-			return
+			return nil
 		}
 
-		if len(args) == 0 {
-			fmt.Println("Error: version is required")
-			return
+		if archivePath != "" {
+			if err := installArchive(cmd, args); err != nil {
+				return fmt.Errorf("failed to install archive: %w", err)
+			}
+			return nil
 		}
 
 		if err := installVersion(cmd, args); err != nil {
-			fmt.Println("Error installing version:", err)
-			return
+			return fmt.Errorf("failed to install version: %w", err)
 		}
 
+		return nil
 	},
 }
 
 func init() {
-	installCmd.Flags().BoolVarP(&forceInstall, "force", "f", false, "Force install even if the version is already installed")
+	installCmd.Flags().BoolVarP(&forceInstall, "force", "f", false, "Reinstall even if the version is already installed")
 	installCmd.Flags().BoolVarP(&listVersions, "list", "l", false, "List all available versions")
-	installCmd.Flags().BoolVarP(&skipExisting, "skip-existing", "s", false, "Skip installation if the version is already installed")
+	installCmd.Flags().BoolVarP(&skipExisting, "skip-existing", "s", false, "Silently skip installation if the version is already installed")
+	installCmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip SHA256 verification of the downloaded archive (e.g. for custom mirrors)")
+	installCmd.Flags().StringVar(&archivePath, "archive", "", "Install from a local Go distribution archive instead of downloading")
+	installCmd.Flags().StringVar(&archiveSHA, "sha256", "", "Expected SHA256 of the archive given with --archive")
 	rootCmd.AddCommand(installCmd)
 }
@@ -3,45 +3,61 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/go-nv/goenv/internal/constants"
 	"github.com/go-nv/goenv/internal/versions"
 	"github.com/spf13/cobra"
 )
 
+var localFormat string
+
 var localCmd = &cobra.Command{
 	Use:   "local [version]",
 	Short: "Set or show the local Go version",
 	Long: `Set or show the local Go version.
 If no version is specified, the current local version will be shown.
-If a version is specified, it will be set as the local version.`,
+If a version is specified, it will be set as the local version.
+
+The version is read from '.go-version' or, for asdf compatibility, from the
+golang line of '.tool-versions' ('.go-version' wins unless
+GOENV_TOOL_VERSIONS=first; GOENV_TOOL_VERSIONS=off ignores '.tool-versions').
+Use --format tool-versions to update the golang line of '.tool-versions' in
+place instead of writing '.go-version'. As asdf does not know goenv aliases,
+an alias is written as the version it points at.
+
+Instead of a single version, '.go-version' may hold a constraint such as
+'>=1.21 <1.23' (comparators: =, !=, <, <=, >, >=, and ~1.21 for any 1.21
+release; alternatives separated by '||'). The newest installed version
+satisfying it is used. With --format tool-versions, the newest installed
+version satisfying the constraint is written instead.`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		vm, err := versions.NewVersionManager()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 
 		// if no version is specified, show the local version
 		if len(args) == 0 {
 			version, err := vm.GetLocalVersion()
 			if err != nil {
-				fmt.Println(err)
-				return
+				return err
 			}
 			fmt.Println(version)
-			return
+			return nil
 		}
 
 		// if a version is specified, set the local version
-		err = vm.SetLocalVersion(args[0])
+		version, err := vm.SetLocalVersion(args[0], localFormat)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
-		fmt.Println("Local version set to", args[0])
+		fmt.Println("Local version set to", version)
+
+		return nil
 	},
 }
 
 func init() {
+	localCmd.Flags().StringVar(&localFormat, "format", constants.FormatGoVersion, "Version file format to write: go-version or tool-versions")
 	rootCmd.AddCommand(localCmd)
 }
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/plugins"
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+// pluginAnnotation marks the commands added for plugins.
+const pluginAnnotation = "goenv_plugin"
+
+// registerPlugins adds a command for every external goenv-<name> executable
+// found under GOENV_ROOT/plugins/*/bin and in PATH, so that plugins run,
+// show in help and complete like built-in commands. Built-in commands take
+// precedence.
+func registerPlugins() {
+	pm, err := plugins.NewPluginManager(cfg)
+	if err != nil {
+		return
+	}
+
+	for _, plugin := range pm.List() {
+		if isBuiltinCommand(plugin.Name) {
+			continue
+		}
+
+		plugin := plugin
+		short := plugin.Summary()
+		if short == "" {
+			short = "External command " + plugin.Path
+		}
+
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                plugin.Name,
+			Short:              short,
+			Hidden:             strings.HasPrefix(plugin.Name, plugins.ShPrefix),
+			DisableFlagParsing: true,
+			Annotations:        map[string]string{pluginAnnotation: plugin.Path},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := pm.Exec(plugin, args, resolvedVersion())
+				return fmt.Errorf("failed to run %s: %w", plugin.Path, err)
+			},
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return pluginCompletions(plugin), cobra.ShellCompDirectiveNoFileComp
+			},
+		})
+	}
+}
+
+// loadPlugins registers the plugin commands if running goenv with args needs
+// them.
+func loadPlugins(args []string) {
+	if needsPlugins(args) {
+		registerPlugins()
+	}
+}
+
+// needsPlugins reports whether running goenv with args needs the plugin
+// commands: for help and completion, which list them, and for commands that
+// are not built in. Other commands skip the search through the plugin
+// directories and PATH.
+func needsPlugins(args []string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			if arg == "--version" || arg == "-v" {
+				return false
+			}
+			continue
+		}
+
+		switch arg {
+		case "help", "completion", "commands", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+		return !isBuiltinCommand(arg)
+	}
+
+	// Without a command goenv shows its help, or installs with
+	// GOENV_AUTO_INSTALL
+	return len(args) > 0 || !cfg.AutoInstall
+}
+
+// isBuiltinCommand reports whether name is a command compiled into goenv,
+// including the help and completion commands cobra adds on execution.
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+
+	for _, cmd := range rootCmd.Commands() {
+		if _, ok := cmd.Annotations[pluginAnnotation]; ok {
+			continue
+		}
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolvedVersion returns the version selected for the current directory,
+// or an empty string if it cannot be determined.
+func resolvedVersion() string {
+	vm, err := versions.NewVersionManager(cfg)
+	if err != nil {
+		return ""
+	}
+
+	resolution, err := vm.Resolve("")
+	if err != nil {
+		return ""
+	}
+
+	version, err := vm.InstalledVersion(resolution)
+	if err != nil {
+		return ""
+	}
+
+	return version
+}
+
+// pluginCompletions returns the completions a plugin lists when run with
+// --complete, like the bash goenv commands.
+func pluginCompletions(plugin plugins.Plugin) []string {
+	if !plugin.SupportsCompletion() {
+		return nil
+	}
+
+	out, err := exec.Command(plugin.Path, "--complete").Output()
+	if err != nil {
+		return nil
+	}
+
+	return strings.Fields(string(out))
+}
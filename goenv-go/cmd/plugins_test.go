@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNeedsPlugins(t *testing.T) {
+	autoInstall := cfg.AutoInstall
+	cfg.AutoInstall = false
+	t.Cleanup(func() { cfg.AutoInstall = autoInstall })
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: nil, want: true},
+		{args: []string{"--help"}, want: true},
+		{args: []string{"help"}, want: true},
+		{args: []string{"help", "install"}, want: true},
+		{args: []string{"completion", "bash"}, want: true},
+		{args: []string{"__complete", "ins"}, want: true},
+		{args: []string{"commands"}, want: true},
+		{args: []string{"some-plugin", "arg"}, want: true},
+		{args: []string{"--version"}, want: false},
+		{args: []string{"version"}, want: false},
+		{args: []string{"install", "1.22.0"}, want: false},
+		{args: []string{"sh-shell", "1.22.0"}, want: false},
+		{args: []string{"--debug", "exec", "go", "version"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := needsPlugins(tt.args); got != tt.want {
+				t.Errorf("needsPlugins(%q) = %t, want %t", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPluginsFromPath(t *testing.T) {
+	autoInstall := cfg.AutoInstall
+	cfg.AutoInstall = false
+	t.Cleanup(func() { cfg.AutoInstall = autoInstall })
+
+	pathDir := t.TempDir()
+	for _, name := range []string{"goenv-hello", "goenv-install"} {
+		if err := os.WriteFile(filepath.Join(pathDir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", pathDir)
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"help"}, want: true},
+		{args: []string{"__complete", "he"}, want: true},
+		{args: []string{"hello"}, want: true},
+		{args: []string{"install", "1.22.0"}, want: false},
+		{args: []string{"version"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Cleanup(removePluginCommands)
+
+			loadPlugins(tt.args)
+
+			var hello, install int
+			for _, cmd := range rootCmd.Commands() {
+				if path, ok := cmd.Annotations[pluginAnnotation]; ok {
+					switch filepath.Base(path) {
+					case "goenv-hello":
+						hello++
+					case "goenv-install":
+						install++
+					}
+				}
+			}
+			if got := hello == 1; got != tt.want {
+				t.Errorf("loadPlugins(%q) registered goenv-hello %d times, want it registered: %t", tt.args, hello, tt.want)
+			}
+			if install != 0 {
+				t.Errorf("loadPlugins(%q) registered goenv-install over the built-in command", tt.args)
+			}
+		})
+	}
+}
+
+// removePluginCommands removes the commands added for plugins.
+func removePluginCommands() {
+	for _, cmd := range rootCmd.Commands() {
+		if _, ok := cmd.Annotations[pluginAnnotation]; ok {
+			rootCmd.RemoveCommand(cmd)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/go-nv/goenv/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var rehashCmd = &cobra.Command{
+	Use:   "rehash",
+	Short: "Rehash goenv shims (run this after installing executables)",
+	Long: `Rehash goenv shims.
+Writes a shim to the shims directory for every executable in the bin
+directory of each installed version and its GOPATH, and removes the shims of
+executables that no longer exist. This runs automatically after install and
+uninstall; run it after installing executables with 'go install'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm, err := shims.NewShimManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		if err := sm.Rehash(); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rehashCmd)
+}
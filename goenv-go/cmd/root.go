@@ -1,38 +1,76 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/runner"
 	"github.com/go-nv/goenv/internal/utils"
 	"github.com/go-nv/goenv/internal/version"
 	"github.com/spf13/cobra"
 )
 
+// cfg holds the goenv settings, read once at startup.
+var cfg *config.Config
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "goenv",
 	Short: "Go version manager",
 	Long: `A simple and powerful Go version manager.
-It allows you to easily switch between multiple versions of Go.`,
+It allows you to easily switch between multiple versions of Go.
+
+` + exitCodesHelp,
 	Version: version.CurrentVersion,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+	// Usage errors are reported by cobra with the usage; errors returned by
+	// the commands are only printed by Execute.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	loadConfig()
+
+	if runner.IsShim(cfg, os.Args[0]) {
+		runShim()
+	}
+
+	loadPlugins(os.Args[1:])
+
+	// Like the bash goenv, a bare `goenv` installs the current version when
+	// GOENV_AUTO_INSTALL=1.
+	if len(os.Args) == 1 && cfg.AutoInstall {
+		rootCmd.SetArgs(append([]string{"install"}, cfg.AutoInstallFlags...))
+	}
+
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
-		os.Exit(1)
+		var status exitStatus
+		if cmd.SilenceErrors && !errors.As(err, &status) {
+			fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
-func init() {
-	if err := utils.InitDirs(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+// loadConfig reads the goenv settings and creates the goenv root. It runs
+// from Execute rather than init, so that importing the package, e.g. in
+// tests, leaves the goenv root alone.
+func loadConfig() {
+	var err error
+	if cfg, err = config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	if err := utils.InitDirs(cfg.RootDir, cfg.VersionsDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+		os.Exit(exitCode(err))
 	}
 }
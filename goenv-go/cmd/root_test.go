@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// TestMain loads the configuration for a temporary goenv root, so that the
+// tests never touch the goenv root and configuration file of the user.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "goenv-cmd-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Setenv(constants.EnvGoenvRootDir, filepath.Join(dir, "root"))
+	os.Setenv(constants.EnvGoenvRcFile, filepath.Join(dir, "goenvrc"))
+	loadConfig()
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestLoadConfigUsesGoenvRoot(t *testing.T) {
+	if want := os.Getenv(constants.EnvGoenvRootDir); cfg.RootDir != want {
+		t.Errorf("RootDir = %s, want %s", cfg.RootDir, want)
+	}
+	if _, err := os.Stat(cfg.VersionsDir()); err != nil {
+		t.Errorf("versions directory not created: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/go-nv/goenv/internal/shell"
+	"github.com/go-nv/goenv/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var onlyManagePaths bool
+
+var shRehashCmd = &cobra.Command{
+	Use:   "sh-rehash [--only-manage-paths]",
+	Short: "Rehash shims and print code updating GOROOT and GOPATH for the shell",
+	Long: `Rehash shims and print code updating GOROOT and GOPATH for the shell.
+Run through the goenv shell function defined by 'goenv init' as 'goenv rehash'.`,
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !onlyManagePaths {
+			sm, err := shims.NewShimManager(cfg)
+			if err == nil {
+				err = sm.Rehash()
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		r, err := runner.NewRunner(cfg)
+		if err != nil {
+			return err
+		}
+
+		version, _, err := r.Version("")
+		if err != nil {
+			return err
+		}
+
+		sh := shell.Current(cfg.Shell)
+		fmt.Print(shell.EnvScript(sh, r.VersionEnv(version), nil))
+
+		// Make the shell forget cached command locations
+		if sh != shell.Fish && sh != shell.Nushell && sh != shell.Elvish {
+			fmt.Println("hash -r 2>/dev/null || true")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	shRehashCmd.Flags().BoolVar(&onlyManagePaths, "only-manage-paths", false, "Only update GOROOT and GOPATH, without rehashing shims")
+	rootCmd.AddCommand(shRehashCmd)
+}
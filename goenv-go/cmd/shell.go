@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/shell"
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var unsetShellVersion bool
+
+var shellCmd = &cobra.Command{
+	Use:   "shell [<version> | - | --unset]",
+	Short: "Set or show the shell-specific Go version",
+	Long: `Set or show the shell-specific Go version.
+Sets a shell-specific Go version by setting the GOENV_VERSION environment
+variable in your shell. This version overrides local application-specific
+versions and the global version. '-' switches back to the previous
+shell-specific version and --unset removes it.
+
+The version may be an installed version, a partial version such as 1.22
+(the newest installed 1.22 release), an alias or 'system'. This command
+needs the shell integration set up by 'goenv init'.`,
+	Aliases: []string{"sh-shell"},
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sh := shell.Current(cfg.Shell)
+
+		// Without the goenv shell function, the output would only be printed
+		if cmd.CalledAs() == "shell" && cfg.Shell == "" {
+			fmt.Fprintln(os.Stderr, `eval "$(goenv init -)" has not been executed.`)
+			fmt.Fprintln(os.Stderr, "Please read the installation instructions in the README.md at github.com/go-nv/goenv")
+			fmt.Fprintln(os.Stderr, "or run 'goenv help init' for more information")
+			return exitStatus(exitError)
+		}
+
+		current := cfg.Version
+
+		switch {
+		case unsetShellVersion:
+			fmt.Print(shellVersionScript(sh, "", current))
+
+		case len(args) == 0:
+			if current == "" {
+				return errors.New("no shell-specific version configured")
+			}
+			// The nushell integration only applies environment changes
+			if sh == shell.Nushell {
+				fmt.Fprintln(os.Stderr, current)
+				fmt.Print(shell.EnvScript(sh, nil, nil))
+				return nil
+			}
+			fmt.Printf("echo %s\n", shell.Quote(current))
+
+		case args[0] == "-":
+			previous, ok := os.LookupEnv(constants.EnvGoenvVersionOld)
+			if !ok {
+				return shellVersionFailed(sh, fmt.Errorf("%s is not set", constants.EnvGoenvVersionOld))
+			}
+			fmt.Print(shellVersionScript(sh, previous, current))
+
+		default:
+			vm, err := versions.NewVersionManager(cfg)
+			if err != nil {
+				return shellVersionFailed(sh, err)
+			}
+
+			version, err := resolveShellVersion(vm, args[0])
+			if err != nil {
+				return shellVersionFailed(sh, err)
+			}
+			fmt.Print(shellVersionScript(sh, version, current))
+		}
+
+		return nil
+	},
+}
+
+// resolveShellVersion checks that version is installed. Exact versions,
+// aliases and constraints are kept as given, partial versions resolve to the
+// newest installed match.
+func resolveShellVersion(vm *versions.VersionManager, version string) (string, error) {
+	_, err := vm.InstalledVersionName(version)
+	if err == nil {
+		return version, nil
+	}
+
+	if installed, ok := vm.LatestInstalledVersion(version); ok {
+		return installed, nil
+	}
+
+	return "", err
+}
+
+// shellVersionScript returns the code setting GOENV_VERSION to version, or
+// unsetting it if version is empty, and remembering current as the previous
+// version.
+func shellVersionScript(sh, version, current string) string {
+	set := []string{constants.EnvGoenvVersionOld + "=" + current}
+	var unset []string
+
+	if version == "" {
+		unset = append(unset, constants.EnvGoenvVersion)
+	} else {
+		set = append(set, constants.EnvGoenvVersion+"="+version)
+	}
+
+	return shell.EnvScript(sh, set, unset)
+}
+
+// shellVersionFailed returns err. Like the bash goenv-sh-shell it prints
+// `false` so that evaluating the output fails too.
+func shellVersionFailed(sh string, err error) error {
+	if sh != shell.Nushell {
+		fmt.Println("false")
+	}
+	return err
+}
+
+func init() {
+	shellCmd.Flags().BoolVar(&unsetShellVersion, "unset", false, "Unset the shell-specific version")
+	rootCmd.AddCommand(shellCmd)
+}
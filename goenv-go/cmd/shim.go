@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-nv/goenv/internal/runner"
+)
+
+// runShim runs the executable the shim goenv was invoked through stands for.
+// Shims are links to the goenv binary named after the executable, so that
+// no shell script runs between the shim and the Go tool.
+func runShim() {
+	r, err := runner.NewRunner(cfg)
+	if err == nil {
+		err = r.RunShim(filepath.Base(os.Args[0]), os.Args[1:])
+	}
+
+	fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+	os.Exit(exitCode(err))
+}
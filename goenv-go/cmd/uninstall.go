@@ -19,18 +19,16 @@ var uninstallCmd = &cobra.Command{
 The version should be in the format of X.Y.Z (e.g., 1.21.0).`,
 	Args:    cobra.ExactArgs(1),
 	Example: "goenv uninstall <version>",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		version := args[0]
 
-		vm, err := versions.NewVersionManager()
+		vm, err := versions.NewVersionManager(cfg)
 		if err != nil {
-			fmt.Println("Error creating version manager:", err)
-			return
+			return fmt.Errorf("failed to create version manager: %w", err)
 		}
 
 		if !vm.IsVersionInstalled(version) {
-			fmt.Printf("version %s is not installed\n", version)
-			return
+			return fmt.Errorf("version '%s' is %w", version, versions.ErrNotInstalled)
 		}
 
 		if !forceUninstall {
@@ -39,22 +37,22 @@ The version should be in the format of X.Y.Z (e.g., 1.21.0).`,
 			fmt.Scanln(&confirm)
 			if confirm != "y" {
 				fmt.Println("uninstall cancelled")
-				return
+				return nil
 			}
 		}
 
-		installer, err := installer.NewInstaller()
+		installer, err := installer.NewInstaller(cfg)
 		if err != nil {
-			fmt.Println("Error creating installer:", err)
-			return
+			return fmt.Errorf("failed to create installer: %w", err)
 		}
 
 		if err := installer.Uninstall(version); err != nil {
-			fmt.Printf("failed to uninstall Go %s: %s\n", version, err)
-			return
+			return fmt.Errorf("failed to uninstall Go %s: %w", version, err)
 		}
 
 		fmt.Printf("Successfully uninstalled Go %s\n", version)
+
+		return nil
 	},
 }
 
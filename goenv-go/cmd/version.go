@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the current Go version and its origin",
+	Long: `Show the currently selected Go version and how it was selected.
+To obtain only the version string, use 'goenv version-name'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		resolution, err := vm.Resolve("")
+		if err != nil {
+			return err
+		}
+
+		version, err := vm.InstalledVersion(resolution)
+		if err != nil {
+			return fmt.Errorf("%w (set by %s)", err, resolution.Origin)
+		}
+
+		fmt.Printf("%s (set by %s)\n", version, resolution.Origin)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var versionFileCmd = &cobra.Command{
+	Use:   "version-file [dir]",
+	Short: "Detect the file that sets the current goenv version",
+	Long: `Detect the file that sets the current goenv version.
+If a directory is specified, only that directory and its parents are searched
+and the command fails if no local version file is found.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			versionFilePath, ok := vm.FindLocalVersionFile(args[0])
+			if !ok {
+				return exitStatus(exitError)
+			}
+			fmt.Println(versionFilePath)
+			return nil
+		}
+
+		versionFilePath, err := vm.FindVersionFile("")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(versionFilePath)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionFileCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var versionNameCmd = &cobra.Command{
+	Use:   "version-name",
+	Short: "Show the current Go version",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		resolution, err := vm.Resolve("")
+		if err != nil {
+			return err
+		}
+
+		version, err := vm.InstalledVersion(resolution)
+		if err != nil {
+			return fmt.Errorf("%w (set by %s)", err, resolution.Origin)
+		}
+
+		fmt.Println(version)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionNameCmd)
+}
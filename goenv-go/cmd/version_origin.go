@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+var versionOriginCmd = &cobra.Command{
+	Use:   "version-origin",
+	Short: "Explain how the current Go version is set",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
+		if err != nil {
+			return err
+		}
+
+		resolution, err := vm.Resolve("")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(resolution.Origin)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionOriginCmd)
+}
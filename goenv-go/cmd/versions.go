@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/go-nv/goenv/internal/versions"
 	"github.com/spf13/cobra"
@@ -10,26 +11,35 @@ import (
 var versionsCmd = &cobra.Command{
 	Use:   "versions",
 	Short: "List all installed Go versions",
-	Long:  `List all Go versions that are currently installed.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		vm, err := versions.NewVersionManager()
+	Long: `List all Go versions that are currently installed.
+Aliases are shown next to the version they point at.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm, err := versions.NewVersionManager(cfg)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 
 		versions, err := vm.ListVersions()
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
+		}
+
+		aliases, err := vm.AliasNames()
+		if err != nil {
+			return err
 		}
 
 		// ToDo: Handle current version
 
 		for _, version := range versions {
+			if names, ok := aliases[version]; ok {
+				fmt.Printf("  %s (%s)\n", version, strings.Join(names, ", "))
+				continue
+			}
 			fmt.Printf("  %s\n", version)
 		}
 
+		return nil
 	},
 }
 
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var whencePaths bool
+
+var whenceCmd = &cobra.Command{
+	Use:   "whence [--path] <command>",
+	Short: "List all Go versions that contain the given executable",
+	Long: `List all Go versions that contain the given executable, in their bin
+directory or in the bin directory of their GOPATH. --path prints the full
+path to the executable instead of the version.`,
+	Example: "goenv whence gopls",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := runner.NewRunner(cfg)
+		if err != nil {
+			return err
+		}
+
+		versions, paths, err := r.Whence(args[0])
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			return exitStatus(exitError)
+		}
+
+		if whencePaths {
+			versions = paths
+		}
+		for _, v := range versions {
+			fmt.Println(v)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	whenceCmd.Flags().BoolVar(&whencePaths, "path", false, "Print the full path to the executable")
+	rootCmd.AddCommand(whenceCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-nv/goenv/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which <command>",
+	Short: "Display the full path to an executable",
+	Long: `Display the full path to an executable.
+Looks up the command in the bin directory of the selected Go version, then
+in the bin directory of its GOPATH (unless GOENV_DISABLE_GOPATH=1). For the
+system version, PATH is searched without the goenv shims directory. When the
+command is not found, the installed versions providing it are listed.`,
+	Example: "goenv which gofmt",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := runner.NewRunner(cfg)
+		if err != nil {
+			return err
+		}
+
+		version, _, err := r.Version("")
+		if err != nil {
+			return err
+		}
+
+		path, err := r.Which(args[0], version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+
+			if versions, _, _ := r.Whence(args[0]); len(versions) > 0 {
+				fmt.Fprintf(os.Stderr, "\nThe '%s' command exists in these Go versions:\n", args[0])
+				for _, v := range versions {
+					fmt.Fprintf(os.Stderr, "  %s\n", v)
+				}
+			}
+			return exitStatus(exitCode(err))
+		}
+
+		fmt.Println(path)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
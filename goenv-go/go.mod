@@ -2,7 +2,10 @@ module github.com/go-nv/goenv
 
 go 1.24.2
 
-require github.com/spf13/cobra v1.9.1
+require (
+	github.com/spf13/cobra v1.9.1
+	golang.org/x/mod v0.24.0
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
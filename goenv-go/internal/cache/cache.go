@@ -0,0 +1,256 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// versionFile records the Go version of a cached archive next to it, so
+// that archives with non-standard filenames are attributed correctly.
+const versionFile = ".version"
+
+// validSHA256 matches the lowercase hex SHA256 naming an entry directory.
+// Anything else, e.g. `../victim` from a hostile release index, must never
+// be joined into a path.
+var validSHA256 = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Cache is a content-addressed store of downloaded Go archives. Each archive
+// lives in a directory named after its SHA256, keeping its original filename.
+type Cache struct {
+	dir string
+}
+
+// Entry is an archive stored in the cache.
+type Entry struct {
+	SHA256   string
+	Filename string
+	Path     string
+	Size     int64
+	ModTime  time.Time // When the archive was stored or last used
+
+	version string // As recorded when the archive was stored
+}
+
+// Version returns the Go version of the archive, as recorded when it was
+// stored. Archives cached without one fall back to the version in their
+// filename (e.g. 1.22.3 for go1.22.3.linux-amd64.tar.gz).
+func (e Entry) Version() string {
+	if e.version != "" {
+		return e.version
+	}
+
+	name := strings.TrimSuffix(e.Filename, "."+constants.ArchiveFormat)
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.TrimPrefix(name, "go")
+}
+
+// New creates a Cache rooted at dir.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the cache directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Lookup returns the cached archive with the given SHA256, if any.
+func (c *Cache) Lookup(sha256 string) (Entry, bool) {
+	entry, err := c.entry(strings.ToLower(sha256))
+	if err != nil {
+		return Entry{}, false
+	}
+
+	return entry, true
+}
+
+// Store copies the verified archive at srcPath of Go version into the cache
+// under sha256.
+func (c *Cache) Store(sha256, filename, version, srcPath string) (Entry, error) {
+	sha256 = strings.ToLower(sha256)
+	if !validSHA256.MatchString(sha256) {
+		return Entry{}, fmt.Errorf("invalid sha256 %q", sha256)
+	}
+	if filename == "" || filepath.Base(filename) != filename || strings.HasPrefix(filename, ".") {
+		return Entry{}, fmt.Errorf("invalid archive file name %q", filename)
+	}
+
+	entryDir := filepath.Join(c.dir, sha256)
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return Entry{}, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so that concurrent readers never see a
+	// partially written archive.
+	tmpFile, err := os.CreateTemp(entryDir, ".tmp-*")
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		tmpFile.Close()
+		return Entry{}, fmt.Errorf("failed to open %s: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	if _, err := io.Copy(tmpFile, src); err != nil {
+		tmpFile.Close()
+		return Entry{}, fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return Entry{}, fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(entryDir, versionFile), []byte(version+"\n"), 0644); err != nil {
+		return Entry{}, fmt.Errorf("failed to write cache metadata: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), filepath.Join(entryDir, filename)); err != nil {
+		return Entry{}, fmt.Errorf("failed to store cache file: %w", err)
+	}
+
+	return c.entry(sha256)
+}
+
+// Touch records that entry was just used, so that pruning by age keeps it.
+func (c *Cache) Touch(entry Entry) error {
+	now := time.Now()
+	if err := os.Chtimes(entry.Path, now, now); err != nil {
+		return fmt.Errorf("failed to update %s in cache: %w", entry.Filename, err)
+	}
+	return nil
+}
+
+// List returns all cached archives, sorted by filename.
+func (c *Cache) List() ([]Entry, error) {
+	dirs, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(dirs))
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		entry, err := c.entry(dir.Name())
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Filename < entries[j].Filename
+	})
+
+	return entries, nil
+}
+
+// Size returns the total size of all cached archives.
+func (c *Cache) Size() (int64, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.Size
+	}
+
+	return size, nil
+}
+
+// Remove deletes a cached archive.
+func (c *Cache) Remove(entry Entry) error {
+	if !validSHA256.MatchString(entry.SHA256) {
+		return fmt.Errorf("invalid sha256 %q", entry.SHA256)
+	}
+	if err := os.RemoveAll(filepath.Join(c.dir, entry.SHA256)); err != nil {
+		return fmt.Errorf("failed to remove %s from cache: %w", entry.Filename, err)
+	}
+	return nil
+}
+
+// Prune removes every cached archive for which keep returns false and
+// returns the removed entries.
+func (c *Cache) Prune(keep func(Entry) bool) ([]Entry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Entry
+	for _, entry := range entries {
+		if keep(entry) {
+			continue
+		}
+		if err := c.Remove(entry); err != nil {
+			return removed, err
+		}
+		removed = append(removed, entry)
+	}
+
+	return removed, nil
+}
+
+// Clear removes the whole cache.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// entry reads the cache entry stored under sha256.
+func (c *Cache) entry(sha256 string) (Entry, error) {
+	if !validSHA256.MatchString(sha256) {
+		return Entry{}, fmt.Errorf("invalid sha256 %q", sha256)
+	}
+
+	entryDir := filepath.Join(c.dir, sha256)
+	files, err := os.ReadDir(entryDir)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	for _, file := range files {
+		if !file.Type().IsRegular() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return Entry{}, err
+		}
+
+		version, _ := os.ReadFile(filepath.Join(entryDir, versionFile))
+
+		return Entry{
+			SHA256:   sha256,
+			Filename: file.Name(),
+			Path:     filepath.Join(entryDir, file.Name()),
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			version:  strings.TrimSpace(string(version)),
+		}, nil
+	}
+
+	return Entry{}, fmt.Errorf("no archive found in %s", entryDir)
+}
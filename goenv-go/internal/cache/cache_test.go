@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Fake SHA256 digests of cached archives
+var (
+	shaA = strings.Repeat("a", 64)
+	shaB = strings.Repeat("b", 64)
+)
+
+func TestStoreRecordsVersion(t *testing.T) {
+	c := New(t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "custom-build.tar.gz")
+	if err := os.WriteFile(src, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := c.Store(strings.ToUpper(shaA), "custom-build.tar.gz", "1.22.3", src)
+	if err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if entry.SHA256 != shaA || entry.Version() != "1.22.3" {
+		t.Errorf("Store() = %s %s, want %s 1.22.3", entry.SHA256, entry.Version(), shaA)
+	}
+
+	entries, err := c.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Filename != "custom-build.tar.gz" || entries[0].Version() != "1.22.3" {
+		t.Errorf("List() = %+v, want the stored archive with version 1.22.3", entries)
+	}
+}
+
+func TestEntryVersionFromFilename(t *testing.T) {
+	entry := Entry{Filename: "go1.21.5.linux-amd64.tar.gz"}
+	if got := entry.Version(); got != "1.21.5" {
+		t.Errorf("Version() = %s, want 1.21.5", got)
+	}
+}
+
+func TestPruneByRecordedVersion(t *testing.T) {
+	c := New(t.TempDir())
+	src := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(src, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Store(shaA, "mirror-copy.tar.gz", "1.22.3", src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store(shaB, "go1.21.0.linux-amd64.tar.gz", "1.21.0", src); err != nil {
+		t.Fatal(err)
+	}
+
+	installed := map[string]bool{"1.22.3": true}
+	removed, err := c.Prune(func(entry Entry) bool {
+		return installed[entry.Version()]
+	})
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 1 || removed[0].SHA256 != shaB {
+		t.Errorf("Prune() removed %+v, want only %s", removed, shaB)
+	}
+
+	if _, ok := c.Lookup(shaA); !ok {
+		t.Error("Prune() removed the archive of an installed version")
+	}
+}
+
+func TestHostileSHA256(t *testing.T) {
+	root := t.TempDir()
+	victim := filepath.Join(root, "victim")
+	if err := os.MkdirAll(victim, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(victim, "archive.tar.gz"), []byte("precious"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(filepath.Join(root, "goenv", "cache"))
+	src := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(src, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sha256 := range []string{"../../victim", "../victim", "/tmp", "", strings.Repeat("g", 64)} {
+		if entry, ok := c.Lookup(sha256); ok {
+			t.Errorf("Lookup(%q) = %+v, want no entry", sha256, entry)
+		}
+		if err := c.Remove(Entry{SHA256: sha256}); err == nil {
+			t.Errorf("Remove(%q) succeeded, want error", sha256)
+		}
+		if _, err := c.Store(sha256, "archive.tar.gz", "1.22.3", src); err == nil {
+			t.Errorf("Store(%q) succeeded, want error", sha256)
+		}
+	}
+	if _, err := c.Store(shaA, "../../victim/archive.tar.gz", "1.22.3", src); err == nil {
+		t.Error("Store() with a path as file name succeeded, want error")
+	}
+
+	content, err := os.ReadFile(filepath.Join(victim, "archive.tar.gz"))
+	if err != nil || string(content) != "precious" {
+		t.Errorf("file outside the cache was touched: %q, %v", content, err)
+	}
+}
+
+func TestTouchRecordsUse(t *testing.T) {
+	c := New(t.TempDir())
+	src := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(src, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := c.Store(shaA, "go1.22.3.linux-amd64.tar.gz", "1.22.3", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(entry.Path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Touch(entry); err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	removed, err := c.Prune(func(entry Entry) bool {
+		return entry.ModTime.After(cutoff)
+	})
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("Prune() removed %+v, want the archive used just now kept", removed)
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// Config holds the goenv settings documented in ENVIRONMENT_VARIABLES.md.
+// It is read once from the environment, falling back to `KEY=value` lines in
+// the goenv configuration file (GOENV_RC_FILE), and passed to the components
+// that need it.
+type Config struct {
+	RootDir            string   // GOENV_ROOT
+	Dir                string   // GOENV_DIR, empty for the current directory
+	Version            string   // GOENV_VERSION
+	Shell              string   // GOENV_SHELL, set by the shell integration
+	Debug              bool     // GOENV_DEBUG
+	HookPath           []string // GOENV_HOOK_PATH
+	GoModVersionEnable bool     // GOENV_GOMOD_VERSION_ENABLE
+	AutoInstall        bool     // GOENV_AUTO_INSTALL
+	AutoInstallFlags   []string // GOENV_AUTO_INSTALL_FLAGS
+	RcFile             string   // GOENV_RC_FILE
+	PathOrder          string   // GOENV_PATH_ORDER
+	DisableGoroot      bool     // GOENV_DISABLE_GOROOT
+	DisableGopath      bool     // GOENV_DISABLE_GOPATH
+	GopathPrefix       string   // GOENV_GOPATH_PREFIX
+	AppendGopath       bool     // GOENV_APPEND_GOPATH
+	PrependGopath      bool     // GOENV_PREPEND_GOPATH
+	CacheDir           string   // GOENV_CACHE_DIR
+	MirrorURLs         []string // GOENV_MIRROR_URL
+	GoBuildMirrorURL   string   // GO_BUILD_MIRROR_URL, only used without GOENV_MIRROR_URL
+	IndexURLs          []string // GOENV_INDEX_URL
+	ToolVersions       string   // GOENV_TOOL_VERSIONS
+	GoWork             string   // GOWORK, as understood by the go command
+}
+
+// Load reads the goenv settings.
+func Load() (*Config, error) {
+	// The home directory is only needed for defaults, so that goenv works
+	// without HOME when GOENV_ROOT is set.
+	homeDir, homeErr := os.UserHomeDir()
+
+	rcFile := os.Getenv(constants.EnvGoenvRcFile)
+	if rcFile == "" && homeErr == nil {
+		rcFile = filepath.Join(homeDir, constants.GoenvRcFile)
+	}
+
+	values := readRcFile(rcFile)
+	get := func(key string) string {
+		if value, ok := os.LookupEnv(key); ok {
+			return value
+		}
+		return values[key]
+	}
+
+	cfg := &Config{
+		RootDir:            strings.TrimSuffix(get(constants.EnvGoenvRootDir), "/"),
+		Dir:                get(constants.EnvGoenvDir),
+		Version:            strings.TrimSpace(get(constants.EnvGoenvVersion)),
+		Shell:              get(constants.EnvGoenvShell),
+		Debug:              get(constants.EnvGoenvDebug) != "",
+		HookPath:           filepath.SplitList(get(constants.EnvGoenvHookPath)),
+		GoModVersionEnable: get(constants.EnvGoenvGoModVersionEnable) == "1",
+		AutoInstall:        get(constants.EnvGoenvAutoInstall) == "1",
+		AutoInstallFlags:   strings.Fields(get(constants.EnvGoenvAutoInstallFlags)),
+		RcFile:             rcFile,
+		PathOrder:          get(constants.EnvGoenvPathOrder),
+		DisableGoroot:      get(constants.EnvGoenvDisableGoroot) == "1",
+		DisableGopath:      get(constants.EnvGoenvDisableGopath) == "1",
+		GopathPrefix:       get(constants.EnvGoenvGopathPrefix),
+		AppendGopath:       get(constants.EnvGoenvAppendGopath) == "1",
+		PrependGopath:      get(constants.EnvGoenvPrependGopath) == "1",
+		CacheDir:           get(constants.EnvGoenvCacheDir),
+		MirrorURLs:         splitList(get(constants.EnvGoenvMirrorURL)),
+		IndexURLs:          splitList(get(constants.EnvGoenvIndexURL)),
+		ToolVersions:       get(constants.EnvGoenvToolVersions),
+		GoWork:             os.Getenv(constants.EnvGoWork),
+	}
+
+	if cfg.RootDir == "" {
+		if homeErr != nil {
+			return nil, fmt.Errorf("failed to get user home directory: %w", homeErr)
+		}
+		cfg.RootDir = filepath.Join(homeDir, constants.GoenvRootDir)
+	}
+	if cfg.GopathPrefix == "" && homeErr == nil {
+		cfg.GopathPrefix = filepath.Join(homeDir, "go")
+	}
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = filepath.Join(cfg.RootDir, constants.CacheDir)
+	}
+	if len(cfg.MirrorURLs) == 0 {
+		// Like go-build, fall back to go.dev when the mirror lacks an archive
+		cfg.GoBuildMirrorURL = strings.TrimSuffix(get(constants.EnvGoBuildMirrorURL), "/")
+		cfg.MirrorURLs = []string{constants.GoDevDlBase}
+	}
+	if len(cfg.IndexURLs) == 0 {
+		cfg.IndexURLs = []string{constants.GoDevDl}
+	}
+
+	return cfg, nil
+}
+
+// VersionsDir returns the directory holding the installed versions.
+func (c *Config) VersionsDir() string {
+	return filepath.Join(c.RootDir, constants.VersionsDir)
+}
+
+// Debugf prints a debug message to stderr when GOENV_DEBUG is set.
+func (c *Config) Debugf(format string, args ...any) {
+	if c.Debug {
+		fmt.Fprintf(os.Stderr, "goenv: "+format+"\n", args...)
+	}
+}
+
+// readRcFile reads the `KEY=value` lines of the goenv configuration file,
+// which is also sourced by the shell integration.
+func readRcFile(rcFile string) map[string]string {
+	values := make(map[string]string)
+
+	if rcFile == "" {
+		return values
+	}
+
+	content, err := os.ReadFile(rcFile)
+	if err != nil {
+		return values
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+
+	return values
+}
+
+// splitList splits a comma-separated list, dropping empty elements.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+func TestLoadGopathFlags(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "", want: false},
+		{value: "0", want: false},
+		{value: "true", want: false},
+		{value: "1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			root := t.TempDir()
+			t.Setenv(constants.EnvGoenvRootDir, root)
+			t.Setenv(constants.EnvGoenvRcFile, filepath.Join(root, "goenvrc"))
+			t.Setenv(constants.EnvGoenvAppendGopath, tt.value)
+			t.Setenv(constants.EnvGoenvPrependGopath, tt.value)
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.AppendGopath != tt.want || cfg.PrependGopath != tt.want {
+				t.Errorf("AppendGopath, PrependGopath = %t, %t, want %t", cfg.AppendGopath, cfg.PrependGopath, tt.want)
+			}
+		})
+	}
+}
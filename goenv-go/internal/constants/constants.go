@@ -13,25 +13,80 @@ const (
 	GlobalVersionFileLegacyDefault = "default" // Default `${HOME}/.goenv/default`
 	GlobalVersionFileLegacyGlobal  = "global"  // Default `${HOME}/.goenv/global`
 	GoModFile                      = "go.mod"
+	GoWorkFile                     = "go.work"
+	ToolVersionsFile               = ".tool-versions" // asdf
 )
 
 const (
 	GoenvRootDir   = ".goenv"   // Default `${HOME}/.goenv`
+	GoenvRcFile    = ".goenvrc" // Default `${HOME}/.goenvrc`
 	ShimsDir       = "shims"    // Default `${HOME}/.goenv/shims`
 	VersionsDir    = "versions" // Default `${HOME}/.goenv/versions`
 	VersionsBinDir = "bin"      // Default `${HOME}/.goenv/versions/bin`
+	StagingDir     = ".staging" // Default `${HOME}/.goenv/.staging`
+	CacheDir       = "cache"    // Default `${HOME}/.goenv/cache`
+	AliasesDir     = "aliases"  // Default `${HOME}/.goenv/aliases`
+	HooksDir       = "goenv.d"  // Default `${HOME}/.goenv/goenv.d`
+	PluginsDir     = "plugins"  // Default `${HOME}/.goenv/plugins`
+)
+
+const (
+	PrototypeShimFile = ".goenv-shim" // In the shims directory, doubles as the rehash lock
+)
+
+const (
+	GoWorkOff = "off" // GOWORK value disabling workspaces
+)
+
+// GOENV_TOOL_VERSIONS values, setting the priority of `.tool-versions` files
+// relative to `.go-version` files in the same directory.
+const (
+	ToolVersionsLast  = "last" // Default
+	ToolVersionsFirst = "first"
+	ToolVersionsOff   = "off"
+)
+
+// Local version file formats
+const (
+	FormatGoVersion    = "go-version"
+	FormatToolVersions = "tool-versions"
 )
 
 const (
 	GoSystemVersion = "system"
+	LatestVersion   = "latest"   // Newest stable release
+	UnstableVersion = "unstable" // Newest release, including betas and release candidates
 )
 
 const (
 	GoDevDl       = "https://go.dev/dl/?mode=json&include=all"
+	GoDevDlBase   = "https://go.dev/dl/"
 	ArchiveFormat = "tar.gz"
 )
 
 // Env variables
 const (
-	EnvGoenvRootDir = "GOENV_ROOT"
+	EnvGoenvRootDir            = "GOENV_ROOT"
+	EnvGoenvCacheDir           = "GOENV_CACHE_DIR"
+	EnvGoenvDir                = "GOENV_DIR"
+	EnvGoenvVersion            = "GOENV_VERSION"
+	EnvGoenvVersionOld         = "GOENV_VERSION_OLD" // Previous shell version, for `goenv shell -`
+	EnvGoenvShell              = "GOENV_SHELL"       // Shell the integration was set up for
+	EnvGoenvDebug              = "GOENV_DEBUG"
+	EnvGoenvHookPath           = "GOENV_HOOK_PATH"
+	EnvGoenvGoModVersionEnable = "GOENV_GOMOD_VERSION_ENABLE"
+	EnvGoenvAutoInstall        = "GOENV_AUTO_INSTALL"
+	EnvGoenvAutoInstallFlags   = "GOENV_AUTO_INSTALL_FLAGS"
+	EnvGoenvRcFile             = "GOENV_RC_FILE"
+	EnvGoenvPathOrder          = "GOENV_PATH_ORDER"
+	EnvGoenvDisableGoroot      = "GOENV_DISABLE_GOROOT"
+	EnvGoenvDisableGopath      = "GOENV_DISABLE_GOPATH"
+	EnvGoenvGopathPrefix       = "GOENV_GOPATH_PREFIX"
+	EnvGoenvAppendGopath       = "GOENV_APPEND_GOPATH"
+	EnvGoenvPrependGopath      = "GOENV_PREPEND_GOPATH"
+	EnvGoenvMirrorURL          = "GOENV_MIRROR_URL" // Comma-separated archive mirrors, tried in order
+	EnvGoenvIndexURL           = "GOENV_INDEX_URL"  // Comma-separated release index URLs, tried in order
+	EnvGoenvToolVersions       = "GOENV_TOOL_VERSIONS"
+	EnvGoBuildMirrorURL        = "GO_BUILD_MIRROR_URL" // go-build mirror serving archives by SHA256
+	EnvGoWork                  = "GOWORK"
 )
@@ -0,0 +1,188 @@
+package goversion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constraint is a version range such as `>=1.21 <1.23`. Comparators separated
+// by spaces must all hold; alternatives are separated by `||`.
+type Constraint struct {
+	raw          string
+	alternatives [][]comparator
+	prerelease   bool
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+// Supported comparison operators, longest first for parsing. `~1.21` allows
+// any release of the 1.21 line, `~1.21.3` any release from 1.21.3 on.
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "~"}
+
+// IsConstraint reports whether s looks like a version constraint rather than
+// a single version.
+func IsConstraint(s string) bool {
+	s = strings.TrimSpace(s)
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return true
+		}
+	}
+	return strings.Contains(s, "||")
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+
+	for _, alternative := range strings.Split(c.raw, "||") {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return Constraint{}, fmt.Errorf("invalid version constraint %q", s)
+		}
+
+		var comparators []comparator
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+
+			op := "="
+			for _, candidate := range operators {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			field = strings.TrimPrefix(field, op)
+
+			// Allow a space between the operator and the version
+			if field == "" && i+1 < len(fields) {
+				i++
+				field = fields[i]
+			}
+
+			v, err := Parse(field)
+			if err != nil || v.IsSystem() {
+				return Constraint{}, fmt.Errorf("invalid version constraint %q", s)
+			}
+			if v.Prerelease != "" {
+				c.prerelease = true
+			}
+
+			comparators = append(comparators, comparator{op: op, version: v})
+		}
+
+		c.alternatives = append(c.alternatives, comparators)
+	}
+
+	return c, nil
+}
+
+// String returns the constraint as written.
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint. Betas and release
+// candidates only satisfy constraints that mention a prerelease themselves.
+func (c Constraint) Check(v Version) bool {
+	if v.IsSystem() || (!v.IsStable() && !c.prerelease) {
+		return false
+	}
+
+	for _, comparators := range c.alternatives {
+		ok := true
+		for _, cmp := range comparators {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cmp comparator) check(v Version) bool {
+	c := v.Compare(cmp.version)
+
+	switch cmp.op {
+	case ">=":
+		return c >= 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case "<":
+		return c < 0
+	case "!=":
+		return c != 0
+	case "~":
+		return c >= 0 && v.Major == cmp.version.Major && v.Minor == cmp.version.Minor
+	default:
+		if cmp.version.IsPartial() {
+			return v.Matches(cmp.version)
+		}
+		return c == 0
+	}
+}
+
+// LatestSatisfying returns the newest version in candidates satisfying c, and
+// false if none does.
+func LatestSatisfying(candidates []string, c Constraint) (string, bool) {
+	var latest string
+	var latestVersion Version
+	found := false
+
+	for _, candidate := range candidates {
+		v, err := Parse(candidate)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if !found || latestVersion.Less(v) {
+			latest, latestVersion, found = candidate, v, true
+		}
+	}
+
+	return latest, found
+}
+
+// Nearest returns the stable versions in candidates next to each version c
+// mentions, the newest one before it and the oldest one from it on, sorted
+// oldest first. It suggests alternatives when no candidate satisfies c.
+func Nearest(candidates []string, c Constraint) []string {
+	var stable []Version
+	for _, candidate := range candidates {
+		if v, err := Parse(candidate); err == nil && !v.IsSystem() && v.IsStable() {
+			stable = append(stable, v)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool { return stable[i].Less(stable[j]) })
+
+	picked := make(map[int]bool)
+	for _, comparators := range c.alternatives {
+		for _, cmp := range comparators {
+			i := sort.Search(len(stable), func(i int) bool { return !stable[i].Less(cmp.version) })
+			for _, j := range []int{i - 1, i} {
+				if j >= 0 && j < len(stable) {
+					picked[j] = true
+				}
+			}
+		}
+	}
+
+	var nearest []string
+	for i, v := range stable {
+		if picked[i] {
+			nearest = append(nearest, v.String())
+		}
+	}
+
+	return nearest
+}
@@ -0,0 +1,134 @@
+package goversion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsConstraint(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{">=1.21", true},
+		{" ~1.21", true},
+		{"1.21 || 1.22", true},
+		{"!=1.21.3", true},
+		{"1.21.3", false},
+		{"latest", false},
+		{"team", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsConstraint(tt.in); got != tt.want {
+			t.Errorf("IsConstraint(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, in := range []string{"", ">=", ">=x", "1.21 ||", ">=system", "=>1.21"} {
+		if _, err := ParseConstraint(in); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=1.21 <1.23", "1.21.0", true},
+		{">=1.21 <1.23", "1.22.9", true},
+		{">=1.21 <1.23", "1.23.0", false},
+		{">=1.21 <1.23", "1.20.14", false},
+		{">= 1.21", "1.21.0", true},
+		{">1.21.3", "1.21.3", false},
+		{">1.21.3", "1.21.4", true},
+		{"<=1.21.3", "1.21.3", true},
+		{"!=1.21.3", "1.21.3", false},
+		{"!=1.21.3", "1.21.4", true},
+		{"=1.21", "1.21.7", true},
+		{"=1.21.3", "1.21.4", false},
+		{"~1.21", "1.21.9", true},
+		{"~1.21", "1.22.0", false},
+		{"~1.21.3", "1.21.2", false},
+		{"~1.21.3", "1.21.3", true},
+		{"1.20 || >=1.22", "1.20.5", true},
+		{"1.20 || >=1.22", "1.21.0", false},
+		{"1.20 || >=1.22", "1.23.1", true},
+		{">=1.21", "1.22rc1", false},
+		{">=1.22rc1", "1.22rc2", true},
+		{">=1.21", "system", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"_"+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+			}
+			if got := c.Check(MustParse(tt.version)); got != tt.want {
+				t.Errorf("%q.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestSatisfying(t *testing.T) {
+	candidates := []string{"1.20.14", "1.21.0", "1.21.13", "1.22.5", "1.23rc1", "custom"}
+
+	tests := []struct {
+		constraint string
+		want       string
+		ok         bool
+	}{
+		{">=1.21 <1.23", "1.22.5", true},
+		{"~1.21", "1.21.13", true},
+		{">=1.23", "", false},
+		{">=1.23rc1", "1.23rc1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, ok := LatestSatisfying(candidates, mustParseConstraint(t, tt.constraint))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("LatestSatisfying(%q) = %q, %v, want %q, %v", tt.constraint, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	candidates := []string{"go1.20.14", "go1.21.0", "go1.21.13", "go1.22.5", "go1.23rc1", "custom"}
+
+	tests := []struct {
+		constraint string
+		want       []string
+	}{
+		{">=1.23", []string{"1.22.5"}},
+		{"<1.20", []string{"1.20.14"}},
+		{">1.21.5 <1.21.10", []string{"1.21.0", "1.21.13"}},
+		{"=1.22.0 || =1.19.0", []string{"1.20.14", "1.21.13", "1.22.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got := Nearest(candidates, mustParseConstraint(t, tt.constraint))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Nearest(%q) = %v, want %v", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func mustParseConstraint(t *testing.T, s string) Constraint {
+	t.Helper()
+	c, err := ParseConstraint(s)
+	if err != nil {
+		t.Fatalf("ParseConstraint(%q) error = %v", s, err)
+	}
+	return c
+}
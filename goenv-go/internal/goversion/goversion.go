@@ -0,0 +1,278 @@
+package goversion
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// Prerelease kinds, ordered from oldest to newest.
+const (
+	Beta = "beta"
+	RC   = "rc"
+)
+
+// Version is a parsed Go release version such as 1.21.0, 1.21rc1 or 1.9.
+type Version struct {
+	Major         int
+	Minor         int
+	Patch         int
+	Prerelease    string // Beta, RC or empty for stable releases
+	PrereleaseNum int
+
+	system   bool
+	hasMinor bool
+	hasPatch bool
+}
+
+// Parse parses a Go version, with or without the `go` prefix. The special
+// `system` version is accepted and sorts before every release.
+func Parse(s string) (Version, error) {
+	s = strings.TrimSpace(s)
+	if s == constants.GoSystemVersion {
+		return Version{system: true}, nil
+	}
+
+	rest := strings.TrimPrefix(s, "go")
+	if rest == "" {
+		return Version{}, fmt.Errorf("invalid Go version %q", s)
+	}
+
+	var v Version
+	var err error
+
+	if v.Major, rest, err = parseNumber(rest); err != nil {
+		return Version{}, fmt.Errorf("invalid Go version %q", s)
+	}
+
+	if strings.HasPrefix(rest, ".") {
+		if v.Minor, rest, err = parseNumber(rest[1:]); err != nil {
+			return Version{}, fmt.Errorf("invalid Go version %q", s)
+		}
+		v.hasMinor = true
+	}
+
+	switch {
+	case v.hasMinor && strings.HasPrefix(rest, "."):
+		if v.Patch, rest, err = parseNumber(rest[1:]); err != nil {
+			return Version{}, fmt.Errorf("invalid Go version %q", s)
+		}
+		v.hasPatch = true
+	case v.hasMinor && strings.HasPrefix(rest, Beta):
+		v.Prerelease = Beta
+		if v.PrereleaseNum, rest, err = parseNumber(rest[len(Beta):]); err != nil {
+			return Version{}, fmt.Errorf("invalid Go version %q", s)
+		}
+	case v.hasMinor && strings.HasPrefix(rest, RC):
+		v.Prerelease = RC
+		if v.PrereleaseNum, rest, err = parseNumber(rest[len(RC):]); err != nil {
+			return Version{}, fmt.Errorf("invalid Go version %q", s)
+		}
+	}
+
+	if rest != "" {
+		return Version{}, fmt.Errorf("invalid Go version %q", s)
+	}
+
+	return v, nil
+}
+
+// MustParse is like Parse but panics if the version cannot be parsed.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsValid reports whether s is a valid Go version.
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// parseNumber parses the leading decimal number of s and returns the rest.
+func parseNumber(s string) (int, string, error) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, s, fmt.Errorf("expected number in %q", s)
+	}
+
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return 0, s, err
+	}
+
+	return n, s[i:], nil
+}
+
+// IsSystem reports whether v is the system version.
+func (v Version) IsSystem() bool {
+	return v.system
+}
+
+// IsStable reports whether v is a stable release.
+func (v Version) IsStable() bool {
+	return !v.system && v.Prerelease == ""
+}
+
+// IsPartial reports whether v omits the minor or patch number, e.g. `1` or
+// `1.22`. Releases before Go 1.21 were published without a patch number, so
+// a partial version may also name an existing release.
+func (v Version) IsPartial() bool {
+	return !v.system && v.Prerelease == "" && !v.hasPatch
+}
+
+// String returns the version in the form used for goenv version directories,
+// without the `go` prefix.
+func (v Version) String() string {
+	if v.system {
+		return constants.GoSystemVersion
+	}
+
+	s := strconv.Itoa(v.Major)
+	if v.hasMinor {
+		s += "." + strconv.Itoa(v.Minor)
+	}
+	if v.hasPatch {
+		s += "." + strconv.Itoa(v.Patch)
+	}
+	if v.Prerelease != "" {
+		s += v.Prerelease + strconv.Itoa(v.PrereleaseNum)
+	}
+
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v sorts before, equal to
+// or after w. Betas sort before release candidates, which sort before the
+// corresponding release; a missing patch number is treated as zero.
+func (v Version) Compare(w Version) int {
+	if v.system || w.system {
+		switch {
+		case v.system && w.system:
+			return 0
+		case v.system:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	if c := compareInt(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+	if c := compareInt(prereleaseRank(v.Prerelease), prereleaseRank(w.Prerelease)); c != 0 {
+		return c
+	}
+
+	return compareInt(v.PrereleaseNum, w.PrereleaseNum)
+}
+
+// Less reports whether v sorts before w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// Matches reports whether v is covered by the version specifier spec: an
+// exact version matches itself, and a partial version such as `1.22` or `1`
+// matches every stable release in that line.
+func (v Version) Matches(spec Version) bool {
+	if v.system || spec.system {
+		return v.system && spec.system
+	}
+
+	if !spec.IsPartial() {
+		return v.Compare(spec) == 0
+	}
+
+	if !v.IsStable() || v.Major != spec.Major {
+		return false
+	}
+
+	return !spec.hasMinor || v.Minor == spec.Minor
+}
+
+func prereleaseRank(prerelease string) int {
+	switch prerelease {
+	case Beta:
+		return 0
+	case RC:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Sort sorts version strings in ascending Go version order. Strings that are
+// not valid Go versions sort after all valid versions, alphabetically.
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Less(versions[i], versions[j])
+	})
+}
+
+// Less reports whether version string a sorts before b, using the ordering
+// documented on Sort.
+func Less(a, b string) bool {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		if c := va.Compare(vb); c != 0 {
+			return c < 0
+		}
+		return a < b
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+// Latest returns the newest version in candidates that matches spec, and
+// false if none does.
+func Latest(candidates []string, spec Version) (string, bool) {
+	var latest string
+	var latestVersion Version
+	found := false
+
+	for _, candidate := range candidates {
+		v, err := Parse(candidate)
+		if err != nil || !v.Matches(spec) {
+			continue
+		}
+		if !found || latestVersion.Less(v) {
+			latest, latestVersion, found = candidate, v, true
+		}
+	}
+
+	return latest, found
+}
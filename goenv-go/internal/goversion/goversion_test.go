@@ -0,0 +1,153 @@
+package goversion
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		partial bool
+		stable  bool
+		wantErr bool
+	}{
+		{in: "1.21.0", want: "1.21.0", stable: true},
+		{in: "go1.22.3", want: "1.22.3", stable: true},
+		{in: " 1.9.7\n", want: "1.9.7", stable: true},
+		{in: "1.22", want: "1.22", partial: true, stable: true},
+		{in: "1", want: "1", partial: true, stable: true},
+		{in: "1.21rc2", want: "1.21rc2"},
+		{in: "go1.22beta1", want: "1.22beta1"},
+		{in: "system", want: "system"},
+		{in: "", wantErr: true},
+		{in: "go", wantErr: true},
+		{in: "latest", wantErr: true},
+		{in: "1.", wantErr: true},
+		{in: "1.22.", wantErr: true},
+		{in: "1.22.x", wantErr: true},
+		{in: "1.22.0.1", wantErr: true},
+		{in: "1rc1", wantErr: true},
+		{in: "1.21rc", wantErr: true},
+		{in: "v1.21.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := Parse(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %s, want error", tt.in, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+
+			if v.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.in, v, tt.want)
+			}
+			if v.IsPartial() != tt.partial {
+				t.Errorf("Parse(%q).IsPartial() = %v, want %v", tt.in, v.IsPartial(), tt.partial)
+			}
+			if v.IsStable() != tt.stable {
+				t.Errorf("Parse(%q).IsStable() = %v, want %v", tt.in, v.IsStable(), tt.stable)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.9.7", "1.10.0", -1},
+		{"1.10.0", "1.21rc2", -1},
+		{"1.21rc2", "1.22.3", -1},
+		{"1.21beta1", "1.21rc1", -1},
+		{"1.21rc1", "1.21rc2", -1},
+		{"1.21rc2", "1.21.0", -1},
+		{"1.21", "1.21.0", 0},
+		{"1.21.0", "go1.21.0", 0},
+		{"1.22.10", "1.22.9", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"system", "1.0.0", -1},
+		{"system", "system", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, b := MustParse(tt.a), MustParse(tt.b)
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := b.Compare(a); got != -tt.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"1.22.3", "custom", "1.21rc2", "1.10.0", "system", "1.9.7", "1.21.0", "1.21beta1", "abc"}
+	Sort(versions)
+
+	want := []string{"system", "1.9.7", "1.10.0", "1.21beta1", "1.21rc2", "1.21.0", "1.22.3", "abc", "custom"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("Sort() = %v, want %v", versions, want)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		version, spec string
+		want          bool
+	}{
+		{"1.22.3", "1.22", true},
+		{"1.22.3", "1", true},
+		{"1.22.3", "1.22.3", true},
+		{"1.22.3", "1.22.4", false},
+		{"1.22.3", "1.21", false},
+		{"1.22rc1", "1.22", false},
+		{"1.22rc1", "1.22rc1", true},
+		{"1.21", "1.21", true},
+		{"system", "system", true},
+		{"1.22.3", "system", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+"_"+tt.spec, func(t *testing.T) {
+			if got := MustParse(tt.version).Matches(MustParse(tt.spec)); got != tt.want {
+				t.Errorf("%s.Matches(%s) = %v, want %v", tt.version, tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatest(t *testing.T) {
+	candidates := []string{"1.21.0", "1.21.5", "1.22.0", "1.22.1", "1.23rc1", "custom"}
+
+	tests := []struct {
+		spec string
+		want string
+		ok   bool
+	}{
+		{"1.21", "1.21.5", true},
+		{"1", "1.22.1", true},
+		{"1.22.0", "1.22.0", true},
+		{"1.23", "", false},
+		{"1.20", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, ok := Latest(candidates, MustParse(tt.spec))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Latest(%s) = %q, %v, want %q, %v", tt.spec, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/utils"
+)
+
+// Hook events. The first five are run at the same points as by the bash
+// goenv.
+const (
+	Exec          = "exec"
+	Rehash        = "rehash"
+	VersionName   = "version-name"
+	VersionOrigin = "version-origin"
+	Which         = "which"
+	PreInstall    = "pre-install"
+	PostInstall   = "post-install"
+	PreUninstall  = "pre-uninstall"
+	PostUninstall = "post-uninstall"
+)
+
+// Events lists the hook events.
+var Events = []string{Exec, Rehash, VersionName, VersionOrigin, Which, PreInstall, PostInstall, PreUninstall, PostUninstall}
+
+// Variables passed to hooks, besides GOENV_ROOT and GOENV_VERSION. Hooks
+// amend the outcome by changing them, e.g. GOENV_VERSION in version-name
+// hooks or GOENV_COMMAND_PATH in which and exec hooks.
+const (
+	EnvEvent           = "GOENV_HOOK_EVENT"
+	EnvVersionFile     = "GOENV_VERSION_FILE"
+	EnvVersionOrigin   = "GOENV_VERSION_ORIGIN"
+	EnvCommand         = "GOENV_COMMAND"
+	EnvCommandPath     = "GOENV_COMMAND_PATH"
+	EnvPrefix          = "GOENV_PREFIX"
+	EnvRegisteredShims = "GOENV_REGISTERED_SHIMS" // Space-separated shim names added by rehash hooks
+)
+
+// systemHookPaths are searched after GOENV_HOOK_PATH and GOENV_ROOT/goenv.d,
+// like in the bash goenv.
+var systemHookPaths = []string{"/usr/local/etc/goenv.d", "/etc/goenv.d", "/usr/lib/goenv/hooks"}
+
+// bashWrapper sources a `.bash` hook, as the bash goenv does, and prints the
+// resulting environment. make_shims and register_shim are provided for
+// rehash hooks.
+const bashWrapper = `set -e
+register_shim() { export GOENV_REGISTERED_SHIMS="${GOENV_REGISTERED_SHIMS:+$GOENV_REGISTERED_SHIMS }$1"; }
+make_shims() { local file; for file; do register_shim "${file##*/}"; done; }
+source "$1" >&2
+export ${!GOENV_@}
+env -0`
+
+// ignoredVars are changed by bash itself when running a hook.
+var ignoredVars = map[string]bool{"_": true, "SHLVL": true, "PWD": true, "OLDPWD": true}
+
+// HookManager finds and runs hook scripts.
+type HookManager struct {
+	cfg *config.Config
+}
+
+// NewHookManager creates a new HookManager instance.
+func NewHookManager(cfg *config.Config) (*HookManager, error) {
+	return &HookManager{cfg: cfg}, nil
+}
+
+// Paths returns the directories searched for hooks: GOENV_HOOK_PATH,
+// GOENV_ROOT/goenv.d, the goenv.d directory next to the goenv installation,
+// the system directories and the etc/goenv.d directory of every plugin.
+func (hm *HookManager) Paths() []string {
+	var paths []string
+	for _, path := range hm.cfg.HookPath {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	paths = append(paths, filepath.Join(hm.cfg.RootDir, constants.HooksDir))
+	if executable, err := os.Executable(); err == nil {
+		installDir := filepath.Dir(filepath.Dir(executable))
+		if installDir != hm.cfg.RootDir {
+			paths = append(paths, filepath.Join(installDir, constants.HooksDir))
+		}
+	}
+	paths = append(paths, systemHookPaths...)
+
+	pluginHooks, _ := filepath.Glob(filepath.Join(hm.cfg.RootDir, constants.PluginsDir, "*", "etc", constants.HooksDir))
+	return append(paths, pluginHooks...)
+}
+
+// Scripts returns the hook scripts for event: `.bash` files, which are
+// sourced like in the bash goenv, and other executable files, in the order
+// of Paths and then by name. Links are resolved.
+func (hm *HookManager) Scripts(event string) []string {
+	var scripts []string
+	seen := make(map[string]bool)
+
+	for _, path := range hm.Paths() {
+		entries, err := os.ReadDir(filepath.Join(path, event))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			script, err := filepath.EvalSymlinks(filepath.Join(path, event, entry.Name()))
+			if err != nil || seen[script] {
+				continue
+			}
+
+			info, err := os.Stat(script)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if !isBashHook(script) && info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+
+			seen[script] = true
+			scripts = append(scripts, script)
+		}
+	}
+
+	return scripts
+}
+
+// Result holds the environment changes made by hooks.
+type Result struct {
+	Env   []string // Variables set or changed, as `KEY=value`
+	Unset []string // Variables removed
+}
+
+// Lookup returns the value a hook set for key.
+func (r *Result) Lookup(key string) (string, bool) {
+	for i := len(r.Env) - 1; i >= 0; i-- {
+		if k, v, _ := strings.Cut(r.Env[i], "="); k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Apply returns env with the changes applied.
+func (r *Result) Apply(env []string) []string {
+	result := make([]string, 0, len(env)+len(r.Env))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, ok := r.Lookup(key); ok || slices.Contains(r.Unset, key) {
+			continue
+		}
+		result = append(result, kv)
+	}
+
+	return append(result, r.Env...)
+}
+
+// Run runs the hooks for event in order. vars holds the `KEY=value` context
+// passed in the environment and, as `{"event": ..., "context": {...}}`, on
+// stdin. Every hook sees the changes made by the previous ones.
+// Executable hooks change variables by printing `KEY=value` or `unset KEY`
+// lines; `.bash` hooks by setting them.
+func (hm *HookManager) Run(event string, vars ...string) (*Result, error) {
+	result := &Result{}
+
+	scripts := hm.Scripts(event)
+	if len(scripts) == 0 {
+		return result, nil
+	}
+
+	vars = append([]string{
+		constants.EnvGoenvRootDir + "=" + hm.cfg.RootDir,
+		EnvEvent + "=" + event,
+	}, vars...)
+
+	for _, script := range scripts {
+		hm.cfg.Debugf("running %s hook %s", event, script)
+
+		context := result.Apply(vars)
+		changes, err := runScript(script, event, context, result.Apply(utils.SetEnv(os.Environ(), vars...)))
+		if err != nil {
+			return nil, fmt.Errorf("%s hook %s failed: %w", event, script, err)
+		}
+
+		result.merge(changes)
+	}
+
+	return result, nil
+}
+
+// runScript runs one hook with env and returns its changes. The context
+// variables are also passed as JSON on stdin.
+func runScript(script, event string, context, env []string) (*Result, error) {
+	input, err := json.Marshal(map[string]any{"event": event, "context": envMap(context)})
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+	if isBashHook(script) {
+		cmd = exec.Command("bash", "-c", bashWrapper, "goenv-hook", script)
+	} else {
+		cmd = exec.Command(script)
+	}
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	if isBashHook(script) {
+		return diffEnv(env, strings.Split(string(out), "\x00")), nil
+	}
+	return parseChanges(out), nil
+}
+
+// parseChanges parses the `KEY=value` and `unset KEY` lines printed by an
+// executable hook. Other lines are ignored.
+func parseChanges(out []byte) *Result {
+	result := &Result{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if key, ok := strings.CutPrefix(line, "unset "); ok {
+			result.merge(&Result{Unset: []string{strings.TrimSpace(key)}})
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		if key, value, ok := strings.Cut(line, "="); ok && key != "" && !strings.ContainsAny(key, " \t#") {
+			result.merge(&Result{Env: []string{key + "=" + value}})
+		}
+	}
+
+	return result
+}
+
+// diffEnv returns the changes between the environments before and after a
+// `.bash` hook.
+func diffEnv(before, after []string) *Result {
+	old := envMap(before)
+	current := make(map[string]bool)
+	result := &Result{}
+
+	for _, kv := range after {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || ignoredVars[key] || strings.HasPrefix(key, "BASH_FUNC_") {
+			continue
+		}
+		current[key] = true
+		if oldValue, existed := old[key]; !existed || oldValue != value {
+			result.Env = append(result.Env, kv)
+		}
+	}
+
+	for _, kv := range before {
+		key, _, _ := strings.Cut(kv, "=")
+		if !current[key] && !ignoredVars[key] {
+			result.Unset = append(result.Unset, key)
+		}
+	}
+	sort.Strings(result.Unset)
+
+	return result
+}
+
+// merge adds the changes of other to r.
+func (r *Result) merge(other *Result) {
+	for _, kv := range other.Env {
+		key, _, _ := strings.Cut(kv, "=")
+		r.Unset = slices.DeleteFunc(r.Unset, func(k string) bool { return k == key })
+		r.Env = removeKey(r.Env, key)
+		r.Env = append(r.Env, kv)
+	}
+	for _, key := range other.Unset {
+		r.Env = removeKey(r.Env, key)
+		if !slices.Contains(r.Unset, key) {
+			r.Unset = append(r.Unset, key)
+		}
+	}
+}
+
+func isBashHook(script string) bool {
+	return strings.HasSuffix(script, ".bash")
+}
+
+func envMap(env []string) map[string]string {
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			m[key] = value
+		}
+	}
+	return m
+}
+
+func removeKey(env []string, key string) []string {
+	return slices.DeleteFunc(env, func(kv string) bool {
+		return strings.HasPrefix(kv, key+"=")
+	})
+}
@@ -0,0 +1,263 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+func TestParseChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want Result
+	}{
+		{
+			name: "assignments",
+			out:  "GOENV_VERSION=1.22.3\nexport GOENV_COMMAND_PATH=/opt/go/bin/go\n",
+			want: Result{Env: []string{"GOENV_VERSION=1.22.3", "GOENV_COMMAND_PATH=/opt/go/bin/go"}},
+		},
+		{
+			name: "unset",
+			out:  "unset GOFLAGS\n",
+			want: Result{Unset: []string{"GOFLAGS"}},
+		},
+		{
+			name: "later lines win",
+			out:  "GOFLAGS=-mod=mod\nunset GOFLAGS\nGOENV_VERSION=1.21.0\nGOENV_VERSION=1.22.3\n",
+			want: Result{Env: []string{"GOENV_VERSION=1.22.3"}, Unset: []string{"GOFLAGS"}},
+		},
+		{
+			name: "other lines ignored",
+			out:  "checking version\n# KEY=value\n=value\nNOT A KEY=value\n\n",
+			want: Result{},
+		},
+		{
+			name: "value with equals sign",
+			out:  "  GOFLAGS=-ldflags=-s  \n",
+			want: Result{Env: []string{"GOFLAGS=-ldflags=-s"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseChanges([]byte(tt.out)); !equalResult(*got, tt.want) {
+				t.Errorf("parseChanges() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+		want   Result
+	}{
+		{
+			name:   "unchanged",
+			before: []string{"HOME=/home/gopher", "GOENV_VERSION=1.22.3"},
+			after:  []string{"GOENV_VERSION=1.22.3", "HOME=/home/gopher"},
+			want:   Result{},
+		},
+		{
+			name:   "changed and added",
+			before: []string{"GOENV_VERSION=1.22.3"},
+			after:  []string{"GOENV_VERSION=1.21.0", "GOFLAGS=-mod=mod"},
+			want:   Result{Env: []string{"GOENV_VERSION=1.21.0", "GOFLAGS=-mod=mod"}},
+		},
+		{
+			name:   "removed",
+			before: []string{"GOFLAGS=-mod=mod", "CGO_ENABLED=0", "HOME=/home/gopher"},
+			after:  []string{"HOME=/home/gopher"},
+			want:   Result{Unset: []string{"CGO_ENABLED", "GOFLAGS"}},
+		},
+		{
+			name:   "bash variables ignored",
+			before: []string{"SHLVL=1", "PWD=/"},
+			after:  []string{"SHLVL=2", "_=/usr/bin/env", "BASH_FUNC_make_shims%%=() {", ""},
+			want:   Result{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffEnv(tt.before, tt.after); !equalResult(*got, tt.want) {
+				t.Errorf("diffEnv() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     Result
+		other Result
+		want  Result
+	}{
+		{
+			name:  "set replaces",
+			r:     Result{Env: []string{"A=1", "B=2"}},
+			other: Result{Env: []string{"A=3"}},
+			want:  Result{Env: []string{"B=2", "A=3"}},
+		},
+		{
+			name:  "set cancels unset",
+			r:     Result{Unset: []string{"A"}},
+			other: Result{Env: []string{"A=1"}},
+			want:  Result{Env: []string{"A=1"}},
+		},
+		{
+			name:  "unset cancels set",
+			r:     Result{Env: []string{"A=1"}},
+			other: Result{Unset: []string{"A", "B"}},
+			want:  Result{Unset: []string{"A", "B"}},
+		},
+		{
+			name:  "unset once",
+			r:     Result{Unset: []string{"A"}},
+			other: Result{Unset: []string{"A"}},
+			want:  Result{Unset: []string{"A"}},
+		},
+		{
+			name:  "same prefix kept",
+			r:     Result{Env: []string{"AB=1"}},
+			other: Result{Env: []string{"A=2"}},
+			want:  Result{Env: []string{"AB=1", "A=2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.merge(&tt.other)
+			if !equalResult(tt.r, tt.want) {
+				t.Errorf("merge() = %+v, want %+v", tt.r, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultApply(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Result
+		env  []string
+		want []string
+	}{
+		{
+			name: "no changes",
+			env:  []string{"A=1", "B=2"},
+			want: []string{"A=1", "B=2"},
+		},
+		{
+			name: "set and unset",
+			r:    Result{Env: []string{"A=3", "C=4"}, Unset: []string{"B"}},
+			env:  []string{"A=1", "B=2", "D=5"},
+			want: []string{"D=5", "A=3", "C=4"},
+		},
+		{
+			name: "empty value",
+			r:    Result{Env: []string{"A="}},
+			env:  []string{"A=1"},
+			want: []string{"A="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Apply(tt.env); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestHookManager returns a HookManager searching only a temporary
+// GOENV_HOOK_PATH besides a temporary root, with the given hooks for event.
+func newTestHookManager(t *testing.T, event string, scripts map[string]string) *HookManager {
+	t.Helper()
+
+	hookDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(hookDir, event), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(hookDir, event, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hm, err := NewHookManager(&config.Config{RootDir: t.TempDir(), HookPath: []string{hookDir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hm
+}
+
+func TestRunExecutableHook(t *testing.T) {
+	if _, err := exec.LookPath("sed"); err != nil {
+		t.Skip("sed not found")
+	}
+
+	// The hook picks the command path from the JSON context on stdin
+	hm := newTestHookManager(t, Which, map[string]string{
+		"10-from-stdin": "#!/bin/sh\nsed -n 's|.*\"GOENV_COMMAND\":\"\\([^\"]*\\)\".*|GOENV_COMMAND_PATH=/opt/\\1|p'\necho\necho unset GOFLAGS\n",
+	})
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	result, err := hm.Run(Which, EnvCommand+"=gofmt", EnvCommandPath+"=/usr/bin/gofmt")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if path, ok := result.Lookup(EnvCommandPath); !ok || path != "/opt/gofmt" {
+		t.Errorf("Lookup(%s) = %q, %t, want /opt/gofmt", EnvCommandPath, path, ok)
+	}
+	if !reflect.DeepEqual(result.Unset, []string{"GOFLAGS"}) {
+		t.Errorf("Unset = %q, want GOFLAGS", result.Unset)
+	}
+}
+
+func TestRunBashHook(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found")
+	}
+
+	// A sourced hook sees the changes of the hooks before it
+	hm := newTestHookManager(t, VersionName, map[string]string{
+		"10-pin":    "#!/bin/sh\necho GOENV_VERSION=1.22.3\n",
+		"20-a.bash": "GOENV_VERSION=\"${GOENV_VERSION%.*}.0\"\n",
+	})
+
+	result, err := hm.Run(VersionName, constants.EnvGoenvVersion+"=1.21.5")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if version, ok := result.Lookup(constants.EnvGoenvVersion); !ok || version != "1.22.0" {
+		t.Errorf("Lookup(%s) = %q, %t, want 1.22.0", constants.EnvGoenvVersion, version, ok)
+	}
+}
+
+func TestRunFailingHook(t *testing.T) {
+	hm := newTestHookManager(t, PreInstall, map[string]string{"fail": "#!/bin/sh\nexit 3\n"})
+
+	if _, err := hm.Run(PreInstall); err == nil {
+		t.Error("Run() succeeded, want the hook failure")
+	}
+}
+
+// equalResult reports whether a and b hold the same changes, treating nil
+// and empty lists alike.
+func equalResult(a, b Result) bool {
+	return len(a.Env) == len(b.Env) && len(a.Unset) == len(b.Unset) &&
+		(len(a.Env) == 0 || reflect.DeepEqual(a.Env, b.Env)) &&
+		(len(a.Unset) == 0 || reflect.DeepEqual(a.Unset, b.Unset))
+}
@@ -0,0 +1,138 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/goversion"
+	"github.com/go-nv/goenv/internal/hooks"
+	"github.com/go-nv/goenv/internal/utils"
+)
+
+const (
+	// archiveVersionFile is the file of a Go distribution archive that
+	// records its version.
+	archiveVersionFile = "go/VERSION"
+	// archiveToolDir holds the toolchain binaries of a Go distribution
+	// archive, in a directory named after their OS and architecture.
+	archiveToolDir = "go/pkg/tool/"
+)
+
+// ArchiveVersion returns the Go version of a distribution archive, read from
+// its go/VERSION file.
+func ArchiveVersion(archivePath string) (string, error) {
+	content, err := utils.ReadArchiveFile(archivePath, archiveVersionFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect Go version of %s: %w", archivePath, err)
+	}
+
+	// The first line holds the version, e.g. go1.22.3; later lines carry
+	// build metadata.
+	line, _, _ := strings.Cut(string(content), "\n")
+	v, err := goversion.Parse(line)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect Go version of %s: %w", archivePath, err)
+	}
+
+	return v.String(), nil
+}
+
+// ArchivePlatform returns the OS and architecture a Go distribution archive
+// was built for, detected from its go/pkg/tool/<os>_<arch> directory.
+func ArchivePlatform(archivePath string) (string, string, error) {
+	var goos, goarch string
+	_, found, err := utils.FindArchiveEntry(archivePath, func(name string) bool {
+		rest, ok := strings.CutPrefix(name, archiveToolDir)
+		if !ok {
+			return false
+		}
+		dir, _, _ := strings.Cut(rest, "/")
+		goos, goarch, ok = strings.Cut(dir, "_")
+		return ok
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to detect platform of %s: %w", archivePath, err)
+	}
+	if !found {
+		return "", "", fmt.Errorf("failed to detect platform of %s: no %s directory", archivePath, archiveToolDir)
+	}
+
+	return goos, goarch, nil
+}
+
+// checkArchivePlatform returns an error if a Go distribution archive was not
+// built for the OS and architecture goenv runs on.
+func checkArchivePlatform(archivePath string) error {
+	goos, goarch, err := ArchivePlatform(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if goos != utils.GetOS() || goarch != utils.GetArch() {
+		return fmt.Errorf("%s is built for %s/%s, not %s/%s", archivePath, goos, goarch, utils.GetOS(), utils.GetArch())
+	}
+
+	return nil
+}
+
+// InstallArchive installs a Go distribution from a local archive and returns
+// the installed version. If sha256 is not empty the archive is verified
+// against it first and added to the download cache.
+func (i *Installer) InstallArchive(archivePath, sha256 string) (string, error) {
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	file := FileRef{
+		Filename: filepath.Base(archivePath),
+		SHA256:   sha256,
+		Kind:     KindArchive,
+	}
+
+	if sha256 != "" {
+		if err := verifyFile(archivePath, file); err != nil {
+			return "", err
+		}
+	}
+
+	version, err := ArchiveVersion(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkArchivePlatform(archivePath); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Installing Go %s from %s\n", version, archivePath)
+
+	if err := i.runHook(hooks.PreInstall, version); err != nil {
+		return "", err
+	}
+
+	stagingDir, err := i.createStagingDir(version)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if sha256 != "" {
+		// Failing to populate the cache does not fail the install
+		if _, err := i.cache.Store(sha256, file.Filename, version, archivePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		}
+	}
+
+	if err := i.installArchive(archivePath, stagingDir, version); err != nil {
+		return "", err
+	}
+
+	i.rehash()
+	if err := i.runHook(hooks.PostInstall, version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
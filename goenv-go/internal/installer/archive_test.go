@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTarGz creates a tar.gz archive holding files, in order.
+func writeTarGz(t *testing.T, files ...[2]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "go.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+	for _, file := range files {
+		header := &tar.Header{Name: file[0], Mode: 0644, Size: int64(len(file[1])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestArchiveVersion(t *testing.T) {
+	path := writeTarGz(t, [2]string{"go/VERSION", "go1.22.3\ntime 2024-05-01T19:59:39Z\n"})
+
+	version, err := ArchiveVersion(path)
+	if err != nil {
+		t.Fatalf("ArchiveVersion() error = %v", err)
+	}
+	if version != "1.22.3" {
+		t.Errorf("ArchiveVersion() = %s, want 1.22.3", version)
+	}
+}
+
+func TestArchivePlatform(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      [][2]string
+		wantOS     string
+		wantArch   string
+		wantFailed bool
+	}{
+		{
+			name:     "tool directory",
+			files:    [][2]string{{"go/VERSION", "go1.22.3\n"}, {"go/pkg/tool/darwin_arm64/compile", ""}},
+			wantOS:   "darwin",
+			wantArch: "arm64",
+		},
+		{
+			name:       "no tool directory",
+			files:      [][2]string{{"go/VERSION", "go1.22.3\n"}, {"go/bin/go", ""}},
+			wantFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goos, goarch, err := ArchivePlatform(writeTarGz(t, tt.files...))
+			if tt.wantFailed {
+				if err == nil {
+					t.Errorf("ArchivePlatform() = %s/%s, want error", goos, goarch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ArchivePlatform() error = %v", err)
+			}
+			if goos != tt.wantOS || goarch != tt.wantArch {
+				t.Errorf("ArchivePlatform() = %s/%s, want %s/%s", goos, goarch, tt.wantOS, tt.wantArch)
+			}
+		})
+	}
+}
@@ -2,33 +2,79 @@ package installer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/go-nv/goenv/internal/cache"
+	"github.com/go-nv/goenv/internal/config"
 	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+	"github.com/go-nv/goenv/internal/hooks"
+	"github.com/go-nv/goenv/internal/shims"
 	"github.com/go-nv/goenv/internal/utils"
 )
 
+// staleStagingAge is the age after which a staging directory without an
+// owner is considered abandoned by an interrupted install.
+const staleStagingAge = time.Hour
+
+// stagingOwnerFile holds the PID of the install using a staging directory,
+// so that concurrent installs do not remove each other's files.
+const stagingOwnerFile = ".goenv-owner"
+
 // Installer handles Go version installation.
 type Installer struct {
-	rootDir string
+	cfg          *config.Config
+	hooks        *hooks.HookManager
+	rootDir      string
+	cache        *cache.Cache
+	skipChecksum bool
+}
+
+// Option configures an Installer.
+type Option func(*Installer)
+
+// WithSkipChecksum disables verification of downloaded archives against the
+// go.dev release index, for mirrors serving archives that are not listed
+// there.
+func WithSkipChecksum(skip bool) Option {
+	return func(i *Installer) {
+		i.skipChecksum = skip
+	}
 }
 
 // NewInstaller creates a new Installer instance.
-func NewInstaller() (*Installer, error) {
-	rootDir, err := utils.GetGoenvRootDir()
+func NewInstaller(cfg *config.Config, opts ...Option) (*Installer, error) {
+	hm, err := hooks.NewHookManager(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get goenv root directory: %w", err)
+		return nil, err
 	}
 
-	return &Installer{
-		rootDir: rootDir,
-	}, nil
+	i := &Installer{
+		cfg:     cfg,
+		hooks:   hm,
+		rootDir: cfg.RootDir,
+		cache:   cache.New(cfg.CacheDir),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i, nil
 }
 
+// ErrNetwork is returned when a download fails because the server could not
+// be reached or did not serve the file.
+var ErrNetwork = errors.New("network failure")
+
 func (i *Installer) makeGetRequest(url string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -37,80 +83,385 @@ func (i *Installer) makeGetRequest(url string) (*http.Response, error) {
 
 	req.Header.Set("User-Agent", utils.GetHTTPUserAgent())
 
-	return http.DefaultClient.Do(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: unexpected response from %s: %s", ErrNetwork, url, resp.Status)
+	}
+
+	return resp, nil
 }
 
-// func (i *Installer) resolveVersion(version string, versions []string) (string, error) {}
+// Resolve resolves a version specifier, as accepted by ResolveVersion, to a
+// release and its archive for the current OS and architecture. The release
+// index is fetched once, so that the version and the archive agree.
+func (i *Installer) Resolve(spec string) (Release, error) {
+	goVersions, err := i.fetchVersions()
+	if err != nil {
+		return Release{}, err
+	}
+
+	version, err := goVersions.Resolve(spec)
+	if err != nil {
+		return Release{}, err
+	}
+
+	file, err := i.archiveFile(goVersions, version, utils.GetOS(), utils.GetArch())
+	if err != nil {
+		return Release{}, err
+	}
+
+	return Release{Version: version, Archive: file}, nil
+}
+
+// archiveFile returns the archive to download for version. Unless checksum
+// verification is disabled, the archive must be listed in the release index.
+func (i *Installer) archiveFile(goVersions GoVersions, version, systemOs, systemArch string) (FileRef, error) {
+	file, err := goVersions.findArchive(version, systemOs, systemArch)
+	if err == nil || !i.skipChecksum {
+		return file, err
+	}
+
+	return FileRef{
+		Filename: fmt.Sprintf("go%s.%s-%s.%s", version, systemOs, systemArch, constants.ArchiveFormat),
+		OS:       systemOs,
+		Arch:     systemArch,
+		Version:  "go" + version,
+		Kind:     KindArchive,
+	}, nil
+}
+
+// Install downloads and installs a release returned by Resolve. The archive is taken from the
+// download cache or downloaded and verified, then extracted into a staging directory under the goenv root and
+// only moved into the versions directory once everything succeeded, replacing
+// any existing installation of the same version.
+func (i *Installer) Install(release Release) error {
+	version, file := release.Version, release.Archive
+
+	fmt.Printf("Installing Go %s for %s/%s\n", version, file.OS, file.Arch)
 
-// Install downloads and installs a Go version.
-func (i *Installer) Install(version string) error {
-	systemOs := utils.GetOS()
-	systemArch := utils.GetArch()
-	versionDir := filepath.Join(i.rootDir, constants.VersionsDir)
+	if err := i.runHook(hooks.PreInstall, version); err != nil {
+		return err
+	}
 
-	fmt.Printf("Installing Go %s for %s/%s\n", version, systemOs, systemArch)
+	stagingDir, err := i.createStagingDir(version)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stagingDir)
 
-	// Download the Go version
-	url := fmt.Sprintf("https://golang.org/dl/go%s.%s-%s.%s", version, systemOs, systemArch, constants.ArchiveFormat)
-	resp, err := i.makeGetRequest(url)
+	archivePath, err := i.fetchArchive(file, stagingDir)
 	if err != nil {
-		return fmt.Errorf("failed to download Go version: %w", err)
+		return err
+	}
+
+	if err := i.installArchive(archivePath, stagingDir, version); err != nil {
+		return err
+	}
+
+	i.rehash()
+	return i.runHook(hooks.PostInstall, version)
+}
+
+// fetchArchive returns the path of a verified archive for file, taken from
+// the cache when possible and downloaded into stagingDir otherwise.
+func (i *Installer) fetchArchive(file FileRef, stagingDir string) (string, error) {
+	if !i.skipChecksum {
+		if entry, ok := i.cache.Lookup(file.SHA256); ok {
+			if err := verifyFile(entry.Path, file); err == nil {
+				fmt.Printf("Using cached %s\n", file.Filename)
+				// Failing to mark the entry as used only shortens its life
+				if err := i.cache.Touch(entry); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+				}
+				return entry.Path, nil
+			}
+			// Drop the corrupted entry and download the archive again
+			i.cache.Remove(entry)
+		}
 	}
-	defer resp.Body.Close()
 
-	// Create a temporary file for the download
-	tmpFile, err := os.CreateTemp("", "go-*.tar.gz")
+	// Download the Go version, trying each mirror in order
+	archivePath := filepath.Join(stagingDir, file.Filename)
+	if err := i.downloadFromMirrors(archivePath, file); err != nil {
+		return "", fmt.Errorf("failed to download Go version: %w", err)
+	}
+
+	if i.skipChecksum {
+		return archivePath, nil
+	}
+
+	// Failing to populate the cache does not fail the install
+	if _, err := i.cache.Store(file.SHA256, file.Filename, strings.TrimPrefix(file.Version, "go"), archivePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+	}
+
+	return archivePath, nil
+}
+
+// downloadFromMirrors downloads file to archivePath from the first mirror
+// that serves a valid copy of it.
+func (i *Installer) downloadFromMirrors(archivePath string, file FileRef) error {
+	var errs []error
+	for _, location := range i.archiveLocations(file) {
+		i.cfg.Debugf("downloading %s from %s", file.Filename, location)
+		err := i.download(location, archivePath, file)
+		if err == nil {
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		errs = append(errs, err)
+		os.Remove(archivePath)
+	}
+
+	return errors.Join(errs...)
+}
+
+// archiveLocations returns the locations file is downloaded from, in order:
+// the GO_BUILD_MIRROR_URL mirror, which serves archives by SHA256 like for
+// go-build, then the configured mirrors.
+func (i *Installer) archiveLocations(file FileRef) []string {
+	var locations []string
+	if i.cfg.GoBuildMirrorURL != "" && file.SHA256 != "" {
+		locations = append(locations, mirrorFileURL(i.cfg.GoBuildMirrorURL, strings.ToLower(file.SHA256)))
+	}
+
+	for _, mirror := range i.cfg.MirrorURLs {
+		locations = append(locations, mirrorFileURL(mirror, file.Filename))
+	}
+
+	return locations
+}
+
+// download saves location to archivePath and verifies it against file.
+func (i *Installer) download(location, archivePath string, file FileRef) error {
+	r, err := i.open(location)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary file: %w", err)
+		return fmt.Errorf("failed to download %s: %w", location, err)
+	}
+	defer r.Close()
+
+	if err := saveFile(archivePath, r); err != nil {
+		return fmt.Errorf("failed to save %s: %w", location, err)
 	}
-	defer os.Remove(tmpFile.Name())
-	defer tmpFile.Close()
 
-	// Save the downloaded file
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
-		return fmt.Errorf("failed to save downloaded file: %w", err)
+	// Verify the archive before extracting anything from it
+	if i.skipChecksum {
+		fmt.Fprintln(os.Stderr, "Warning: skipping checksum verification")
+		return nil
+	}
+	if err := verifyFile(archivePath, file); err != nil {
+		return fmt.Errorf("%s: %w", location, err)
 	}
 
-	// Extract the archive
-	if err := utils.ExtractArchive(tmpFile.Name(), filepath.Join(versionDir, version)); err != nil {
+	return nil
+}
+
+// installArchive extracts archivePath inside stagingDir and moves the result
+// into place as version.
+func (i *Installer) installArchive(archivePath, stagingDir, version string) error {
+	extractDir := filepath.Join(stagingDir, version)
+	if err := utils.ExtractArchive(archivePath, extractDir); err != nil {
 		return fmt.Errorf("failed to extract archive: %w", err)
 	}
 
+	versionsDir := i.cfg.VersionsDir()
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create versions directory: %w", err)
+	}
+
+	// Move an existing installation aside so that it can be restored if the
+	// new one cannot be moved into place.
+	targetDir := filepath.Join(versionsDir, version)
+	previousDir := filepath.Join(stagingDir, version+".previous")
+	if _, err := os.Lstat(targetDir); err == nil {
+		if err := os.Rename(targetDir, previousDir); err != nil {
+			return fmt.Errorf("failed to replace existing installation: %w", err)
+		}
+	}
+
+	if err := os.Rename(extractDir, targetDir); err != nil {
+		if _, statErr := os.Lstat(previousDir); statErr == nil {
+			os.Rename(previousDir, targetDir)
+		}
+		return fmt.Errorf("failed to move installation into place: %w", err)
+	}
+
 	return nil
 }
 
+// createStagingDir removes leftovers of interrupted installs and creates a
+// fresh staging directory for version.
+func (i *Installer) createStagingDir(version string) (string, error) {
+	stagingRoot := filepath.Join(i.rootDir, constants.StagingDir)
+	if err := os.MkdirAll(stagingRoot, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	if err := cleanStagingDir(stagingRoot); err != nil {
+		return "", err
+	}
+
+	stagingDir, err := os.MkdirTemp(stagingRoot, version+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	owner := filepath.Join(stagingDir, stagingOwnerFile)
+	if err := os.WriteFile(owner, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		os.RemoveAll(stagingDir)
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	return stagingDir, nil
+}
+
+// cleanStagingDir removes the staging directories left by interrupted
+// installs: those whose owning process no longer runs and, for directories
+// without an owner, those older than staleStagingAge. Directories of
+// installs still running, including of the same version, are kept.
+func cleanStagingDir(stagingRoot string) error {
+	entries, err := os.ReadDir(stagingRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read staging directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		dir := filepath.Join(stagingRoot, entry.Name())
+
+		stale := false
+		if pid, ok := stagingOwner(dir); ok {
+			stale = !processExists(pid)
+		} else if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > staleStagingAge {
+			stale = true
+		}
+
+		if stale {
+			if err := os.RemoveAll(dir); err != nil {
+				return fmt.Errorf("failed to remove stale staging directory: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// stagingOwner returns the PID of the install owning a staging directory.
+func stagingOwner(dir string) (int, bool) {
+	content, err := os.ReadFile(filepath.Join(dir, stagingOwnerFile))
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	return pid, err == nil && pid > 0
+}
+
+// processExists reports whether a process with the given PID is running.
+func processExists(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// saveFile writes r to a new file at path.
+func saveFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
 // Uninstall removes a Go version.
 func (i *Installer) Uninstall(version string) error {
-	versionDir := filepath.Join(i.rootDir, constants.VersionsDir, version)
+	if err := i.runHook(hooks.PreUninstall, version); err != nil {
+		return err
+	}
+
+	versionDir := filepath.Join(i.cfg.VersionsDir(), version)
 	if err := os.RemoveAll(versionDir); err != nil {
 		return fmt.Errorf("failed to remove version directory: %w", err)
 	}
-	return nil
+
+	i.rehash()
+	return i.runHook(hooks.PostUninstall, version)
 }
 
-// ListAvailableVersions returns a list of available Go versions.
+// runHook runs the install or uninstall hooks for event. A failing
+// pre-install or pre-uninstall hook aborts the operation.
+func (i *Installer) runHook(event, version string) error {
+	_, err := i.hooks.Run(event,
+		constants.EnvGoenvVersion+"="+version,
+		hooks.EnvPrefix+"="+filepath.Join(i.cfg.VersionsDir(), version),
+	)
+	return err
+}
+
+// rehash updates the shims after the installed versions changed. The
+// installation itself succeeded, so a failure is only reported.
+func (i *Installer) rehash() {
+	sm, err := shims.NewShimManager(i.cfg)
+	if err == nil {
+		err = sm.Rehash()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to rehash shims: %s\n", err)
+	}
+}
+
+// ListAvailableVersions returns a list of available Go versions, oldest first.
 func (i *Installer) ListAvailableVersions() ([]string, error) {
-	resp, err := i.makeGetRequest(constants.GoDevDl)
+	goVersions, err := i.fetchVersions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	versions := make([]string, len(goVersions))
+	for i, version := range goVersions {
+		versions[i] = strings.TrimPrefix(version.Version, "go")
 	}
+	goversion.Sort(versions)
+
+	return versions, nil
+}
 
-	defer resp.Body.Close()
+// fetchVersions downloads the release index, trying each configured index
+// location in order.
+func (i *Installer) fetchVersions() (GoVersions, error) {
+	r, err := i.openFirst(i.cfg.IndexURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release index: %w", err)
+	}
+	defer r.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var _versions GoVersions
-	if err := json.Unmarshal(body, &_versions); err != nil {
+	var goVersions GoVersions
+	if err := json.Unmarshal(body, &goVersions); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal versions: %w", err)
 	}
-
-	versions := make([]string, len(_versions))
-	for i, version := range _versions {
-		versions[i] = version.Version
+	if err := goVersions.validate(); err != nil {
+		return nil, err
 	}
 
-	return versions, nil
+	return goVersions, nil
 }
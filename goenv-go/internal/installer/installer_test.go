@@ -0,0 +1,100 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-nv/goenv/internal/config"
+)
+
+func TestCleanStagingDir(t *testing.T) {
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+
+	stagingRoot := t.TempDir()
+	dirs := map[string]struct {
+		owner string
+		old   bool
+		keep  bool
+	}{
+		"1.22.0-running":   {owner: strconv.Itoa(os.Getpid()), old: true, keep: true},
+		"1.22.0-exited":    {owner: strconv.Itoa(exited.Process.Pid), keep: false},
+		"1.21.0-orphan":    {old: true, keep: false},
+		"1.22.0-unclaimed": {keep: true},
+	}
+
+	for name, dir := range dirs {
+		path := filepath.Join(stagingRoot, name)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if dir.owner != "" {
+			if err := os.WriteFile(filepath.Join(path, stagingOwnerFile), []byte(dir.owner), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if dir.old {
+			past := time.Now().Add(-2 * staleStagingAge)
+			if err := os.Chtimes(path, past, past); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := cleanStagingDir(stagingRoot); err != nil {
+		t.Fatalf("cleanStagingDir() error = %v", err)
+	}
+
+	for name, dir := range dirs {
+		_, err := os.Stat(filepath.Join(stagingRoot, name))
+		if kept := err == nil; kept != dir.keep {
+			t.Errorf("%s kept = %v, want %v", name, kept, dir.keep)
+		}
+	}
+}
+
+func TestArchiveLocations(t *testing.T) {
+	file := FileRef{Filename: "go1.22.0.linux-amd64.tar.gz", SHA256: "ABC123"}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want []string
+	}{
+		{
+			name: "mirrors in order",
+			cfg:  config.Config{MirrorURLs: []string{"https://a.example/", "https://b.example"}},
+			want: []string{
+				"https://a.example/go1.22.0.linux-amd64.tar.gz",
+				"https://b.example/go1.22.0.linux-amd64.tar.gz",
+			},
+		},
+		{
+			name: "go-build mirror first",
+			cfg: config.Config{
+				GoBuildMirrorURL: "https://build.example",
+				MirrorURLs:       []string{"https://go.dev/dl/"},
+			},
+			want: []string{
+				"https://build.example/abc123",
+				"https://go.dev/dl/go1.22.0.linux-amd64.tar.gz",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Installer{cfg: &tt.cfg}
+			if got := i.archiveLocations(file); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("archiveLocations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mirrorFileURL returns the location of filename on mirror.
+func mirrorFileURL(mirror, filename string) string {
+	if isLocalLocation(mirror) {
+		return filepath.Join(localPath(mirror), filename)
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + filename
+}
+
+// open opens location, which may be an http(s) URL, a file:// URL or a plain
+// path on disk.
+func (i *Installer) open(location string) (io.ReadCloser, error) {
+	if isLocalLocation(location) {
+		return os.Open(localPath(location))
+	}
+
+	resp, err := i.makeGetRequest(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// openFirst opens the first of locations that can be opened, trying them in
+// order.
+func (i *Installer) openFirst(locations []string) (io.ReadCloser, error) {
+	var errs []error
+	for _, location := range locations {
+		i.cfg.Debugf("fetching %s", location)
+		r, err := i.open(location)
+		if err == nil {
+			return r, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", location, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// isLocalLocation reports whether location refers to the local filesystem.
+func isLocalLocation(location string) bool {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" || u.Scheme == "file" {
+		return true
+	}
+	// Windows drive letters parse as a single-letter scheme
+	return len(u.Scheme) == 1
+}
+
+// localPath converts a file:// URL or plain path to a filesystem path.
+func localPath(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path)
+	}
+	return location
+}
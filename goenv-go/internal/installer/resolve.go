@@ -0,0 +1,101 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+)
+
+// ErrVersionNotFound is returned when a version is not available from the
+// release index.
+var ErrVersionNotFound = errors.New("not found")
+
+// ResolveVersion resolves a version specifier against the go.dev release
+// index. It accepts `latest` (newest stable release), `unstable` (newest
+// release including betas and release candidates), partial versions such as
+// `1.22` or `1` (newest stable patch release), constraints such as
+// `>=1.21 <1.23` (newest release satisfying them) and exact versions, with or
+// without the `go` prefix.
+func (i *Installer) ResolveVersion(version string) (string, error) {
+	goVersions, err := i.fetchVersions()
+	if err != nil {
+		return "", err
+	}
+
+	return goVersions.Resolve(version)
+}
+
+// Resolve resolves a version specifier against the list of Go versions and
+// returns the newest matching version.
+func (gv GoVersions) Resolve(version string) (string, error) {
+	spec := strings.TrimSpace(version)
+
+	if goversion.IsConstraint(spec) {
+		return gv.resolveConstraint(spec)
+	}
+
+	var want goversion.Version
+	if spec != constants.LatestVersion && spec != constants.UnstableVersion {
+		var err error
+		if want, err = goversion.Parse(spec); err != nil {
+			return "", err
+		}
+	}
+
+	var latest *goversion.Version
+	for _, goVersion := range gv {
+		v, err := goversion.Parse(goVersion.Version)
+		if err != nil {
+			continue
+		}
+
+		switch spec {
+		case constants.LatestVersion:
+			if !goVersion.Stable {
+				continue
+			}
+		case constants.UnstableVersion:
+		default:
+			if !v.Matches(want) {
+				continue
+			}
+		}
+
+		if latest == nil || latest.Less(v) {
+			latest = &v
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("Go version matching %q %w", version, ErrVersionNotFound)
+	}
+
+	return latest.String(), nil
+}
+
+// resolveConstraint returns the newest version satisfying a version
+// constraint.
+func (gv GoVersions) resolveConstraint(spec string) (string, error) {
+	constraint, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make([]string, 0, len(gv))
+	for _, goVersion := range gv {
+		candidates = append(candidates, goVersion.Version)
+	}
+
+	version, ok := goversion.LatestSatisfying(candidates, constraint)
+	if !ok {
+		if nearest := goversion.Nearest(candidates, constraint); len(nearest) > 0 {
+			return "", fmt.Errorf("Go version satisfying '%s' %w (nearest releases: %s)", constraint, ErrVersionNotFound, strings.Join(nearest, ", "))
+		}
+		return "", fmt.Errorf("Go version satisfying '%s' %w", constraint, ErrVersionNotFound)
+	}
+
+	return goversion.MustParse(version).String(), nil
+}
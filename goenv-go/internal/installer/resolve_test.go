@@ -0,0 +1,200 @@
+package installer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/utils"
+)
+
+func TestResolve(t *testing.T) {
+	gv := GoVersions{
+		{Version: "go1.23rc1"},
+		{Version: "go1.22.5", Stable: true},
+		{Version: "go1.22.0", Stable: true},
+		{Version: "go1.21.13", Stable: true},
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "latest", want: "1.22.5"},
+		{spec: "unstable", want: "1.23rc1"},
+		{spec: "1.21", want: "1.21.13"},
+		{spec: "go1.22.0", want: "1.22.0"},
+		{spec: ">=1.21 <1.22", want: "1.21.13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := gv.Resolve(tt.spec)
+			if err != nil {
+				t.Fatalf("Resolve(%q) error = %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveConstraintNotFound(t *testing.T) {
+	gv := GoVersions{
+		{Version: "go1.22.5", Stable: true},
+		{Version: "go1.21.13", Stable: true},
+		{Version: "go1.20.14", Stable: true},
+	}
+
+	_, err := gv.Resolve(">=1.21.14 <1.22")
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("Resolve() error = %v, want %v", err, ErrVersionNotFound)
+	}
+	if !strings.Contains(err.Error(), "nearest releases: 1.21.13, 1.22.5") {
+		t.Errorf("Resolve() error = %q, want the nearest releases listed", err)
+	}
+}
+
+func TestInstallerResolveFetchesIndexOnce(t *testing.T) {
+	goos, goarch := utils.GetOS(), utils.GetArch()
+	index := GoVersions{
+		{Version: "go1.22.3", Stable: true, Files: []FileRef{
+			{Filename: "go1.22.3." + goos + "-" + goarch + ".tar.gz", OS: goos, Arch: goarch, Version: "go1.22.3", Kind: KindArchive},
+		}},
+		{Version: "go1.22.0", Stable: true},
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(index)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{RootDir: t.TempDir(), IndexURLs: []string{server.URL}}
+	i, err := NewInstaller(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := i.Resolve("1.22")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if release.Version != "1.22.3" || release.Archive.Filename != index[0].Files[0].Filename {
+		t.Errorf("Resolve() = %+v, want 1.22.3 and its archive", release)
+	}
+	if requests != 1 {
+		t.Errorf("release index fetched %d times, want once", requests)
+	}
+}
+
+func TestFetchVersionsRejectsUnsafeFilenames(t *testing.T) {
+	tests := []struct {
+		filename string
+		ok       bool
+	}{
+		{filename: "go1.22.3.linux-amd64.tar.gz", ok: true},
+		{filename: "../../../home/evil"},
+		{filename: "sub/go.tar.gz"},
+		{filename: `sub\go.tar.gz`},
+		{filename: "/etc/passwd"},
+		{filename: ".."},
+		{filename: "."},
+		{filename: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			root := t.TempDir()
+			indexPath := filepath.Join(root, "index.json")
+			index := GoVersions{{Version: "go1.22.3", Stable: true, Files: []FileRef{{Filename: tt.filename, Kind: KindArchive}}}}
+			content, err := json.Marshal(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(indexPath, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			i, err := NewInstaller(&config.Config{RootDir: root, IndexURLs: []string{indexPath}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = i.fetchVersions()
+			if tt.ok != (err == nil) {
+				t.Errorf("fetchVersions() error = %v, want ok %t", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHostileIndexLeavesOutsideAlone(t *testing.T) {
+	root := t.TempDir()
+	victim := filepath.Join(root, "victim")
+	if err := os.MkdirAll(victim, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(victim, "keep"), []byte("precious"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	goos, goarch := utils.GetOS(), utils.GetArch()
+	file := FileRef{
+		Filename: "go1.22.3." + goos + "-" + goarch + ".tar.gz",
+		OS:       goos,
+		Arch:     goarch,
+		Version:  "go1.22.3",
+		SHA256:   "../../victim",
+		Kind:     KindArchive,
+	}
+
+	mirror := filepath.Join(root, "mirror")
+	if err := os.MkdirAll(mirror, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mirror, file.Filename), []byte("not go"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	indexPath := filepath.Join(root, "index.json")
+	content, err := json.Marshal(GoVersions{{Version: "go1.22.3", Stable: true, Files: []FileRef{file}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(indexPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	goenvRoot := filepath.Join(root, "goenv")
+	i, err := NewInstaller(&config.Config{
+		RootDir:    goenvRoot,
+		CacheDir:   filepath.Join(goenvRoot, "cache"),
+		IndexURLs:  []string{indexPath},
+		MirrorURLs: []string{mirror},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := i.Resolve("1.22.3"); err == nil {
+		t.Error("Resolve() accepted an index with a hostile sha256")
+	}
+
+	// Even if such an entry got past the index, the cache must not use it
+	stagingDir := t.TempDir()
+	if _, err := i.fetchArchive(file, stagingDir); err == nil {
+		t.Error("fetchArchive() succeeded, want a checksum error")
+	}
+
+	if content, err := os.ReadFile(filepath.Join(victim, "keep")); err != nil || string(content) != "precious" {
+		t.Errorf("directory outside the cache was touched: %q, %v", content, err)
+	}
+}
@@ -1,5 +1,19 @@
 package installer
 
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// File kinds published in the go.dev release index.
+const (
+	KindSource    = "source"
+	KindArchive   = "archive"
+	KindInstaller = "installer"
+)
+
 // GoVersions is a list of Go versions.
 type GoVersions []GoVersion
 
@@ -20,3 +34,41 @@ type FileRef struct {
 	Size     int64  `json:"size"`
 	Kind     string `json:"kind"` // Can be "source", "archive", or "installer"
 }
+
+// Release is a Go version resolved against the release index, with the
+// archive to install it from.
+type Release struct {
+	Version string
+	Archive FileRef
+}
+
+// validate rejects a release index with file names that are not plain file
+// names or checksums that are not SHA256 hex digests, as they end up in
+// download URLs and paths of the staging directory and the cache.
+func (gv GoVersions) validate() error {
+	for _, goVersion := range gv {
+		for _, file := range goVersion.Files {
+			if !isPlainFilename(file.Filename) {
+				return fmt.Errorf("invalid file name %q for Go %s in the release index", file.Filename, goVersion.Version)
+			}
+			if file.SHA256 != "" && !isHexSHA256(file.SHA256) {
+				return fmt.Errorf("invalid sha256 %q of %s in the release index", file.SHA256, file.Filename)
+			}
+		}
+	}
+	return nil
+}
+
+// isPlainFilename reports whether name is a file name without any directory.
+func isPlainFilename(name string) bool {
+	return name != "" && name != "." && filepath.Base(name) == name && !strings.ContainsAny(name, `/\`) && !strings.Contains(name, "..")
+}
+
+// isHexSHA256 reports whether s is a hex SHA256 digest.
+func isHexSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when a downloaded archive does not match the
+// size or SHA256 published in the go.dev release index.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// findArchive returns the archive FileRef of version for the given OS and
+// architecture.
+func (gv GoVersions) findArchive(version, goos, goarch string) (FileRef, error) {
+	for _, goVersion := range gv {
+		if strings.TrimPrefix(goVersion.Version, "go") != version {
+			continue
+		}
+
+		for _, file := range goVersion.Files {
+			if file.Kind == KindArchive && file.OS == goos && file.Arch == goarch {
+				return file, nil
+			}
+		}
+
+		return FileRef{}, fmt.Errorf("archive of Go %s for %s/%s %w", version, goos, goarch, ErrVersionNotFound)
+	}
+
+	return FileRef{}, fmt.Errorf("Go %s %w in the release index", version, ErrVersionNotFound)
+}
+
+// verifyFile checks the size and SHA256 of the file at path against file.
+func verifyFile(path string, file FileRef) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if file.Size > 0 && size != file.Size {
+		return fmt.Errorf("%w for %s: expected %d bytes, got %d", ErrChecksumMismatch, file.Filename, file.Size, size)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if file.SHA256 == "" {
+		return fmt.Errorf("%w for %s: no sha256 published, got %s", ErrChecksumMismatch, file.Filename, sum)
+	}
+	if !strings.EqualFold(sum, file.SHA256) {
+		return fmt.Errorf("%w for %s: expected sha256 %s, got %s", ErrChecksumMismatch, file.Filename, file.SHA256, sum)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeArchive writes content to a file and returns its path and SHA256.
+func writeArchive(t *testing.T, content string) (string, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "go.tar.gz")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	return path, hex.EncodeToString(sum[:])
+}
+
+func TestVerifyFile(t *testing.T) {
+	const content = "go archive"
+	path, sum := writeArchive(t, content)
+	otherSum := sha256.Sum256([]byte("other"))
+
+	tests := []struct {
+		name     string
+		file     FileRef
+		mismatch bool
+	}{
+		{
+			name: "matching checksum and size",
+			file: FileRef{Filename: "go.tar.gz", SHA256: sum, Size: int64(len(content))},
+		},
+		{
+			name: "matching checksum in upper case without size",
+			file: FileRef{Filename: "go.tar.gz", SHA256: strings.ToUpper(sum)},
+		},
+		{
+			name:     "checksum mismatch",
+			file:     FileRef{Filename: "go.tar.gz", SHA256: hex.EncodeToString(otherSum[:])},
+			mismatch: true,
+		},
+		{
+			name:     "size mismatch",
+			file:     FileRef{Filename: "go.tar.gz", SHA256: sum, Size: int64(len(content)) + 1},
+			mismatch: true,
+		},
+		{
+			name:     "missing checksum",
+			file:     FileRef{Filename: "go.tar.gz"},
+			mismatch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyFile(path, tt.file)
+			if tt.mismatch != errors.Is(err, ErrChecksumMismatch) {
+				t.Errorf("verifyFile() error = %v, want checksum mismatch: %v", err, tt.mismatch)
+			}
+			if !tt.mismatch && err != nil {
+				t.Errorf("verifyFile() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyFileMissing(t *testing.T) {
+	err := verifyFile(filepath.Join(t.TempDir(), "missing"), FileRef{SHA256: "00"})
+	if err == nil || errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("verifyFile() error = %v, want open error", err)
+	}
+}
+
+func TestFindArchiveUnstable(t *testing.T) {
+	const content = "go rc archive"
+	path, sum := writeArchive(t, content)
+
+	goVersions := GoVersions{
+		{Version: "go1.22.0", Stable: true, Files: []FileRef{
+			{Filename: "go1.22.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: KindArchive, SHA256: "00"},
+		}},
+		{Version: "go1.23rc1", Stable: false, Files: []FileRef{
+			{Filename: "go1.23rc1.src.tar.gz", Kind: KindSource, SHA256: "00"},
+			{Filename: "go1.23rc1.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: KindArchive, SHA256: sum, Size: int64(len(content))},
+		}},
+	}
+
+	file, err := goVersions.findArchive("1.23rc1", "linux", "amd64")
+	if err != nil {
+		t.Fatalf("findArchive() error = %v", err)
+	}
+	if err := verifyFile(path, file); err != nil {
+		t.Errorf("verifyFile() error = %v", err)
+	}
+
+	if _, err := goVersions.findArchive("1.23rc1", "darwin", "arm64"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("findArchive() for a missing platform error = %v, want not found", err)
+	}
+	if _, err := goVersions.findArchive("1.24.0", "linux", "amd64"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("findArchive() for a missing version error = %v, want not found", err)
+	}
+}
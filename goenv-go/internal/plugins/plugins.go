@@ -0,0 +1,145 @@
+package plugins
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/utils"
+)
+
+// Prefix is the prefix of plugin executables: `goenv foo` runs `goenv-foo`.
+const Prefix = constants.ProjectName + "-"
+
+// ShPrefix marks plugin commands whose output is evaluated by the goenv
+// shell function, e.g. `goenv-sh-foo` for `goenv foo`.
+const ShPrefix = "sh-"
+
+// EnvResolvedVersion passes the version selected for the current directory to
+// plugins, without overriding GOENV_VERSION.
+const EnvResolvedVersion = "GOENV_RESOLVED_VERSION"
+
+// summaryScanLimit is how many lines of a script are searched for its
+// `# Summary:` comment.
+const summaryScanLimit = 30
+
+// Plugin is an external goenv command.
+type Plugin struct {
+	Name string // Command name, without the goenv- prefix
+	Path string
+}
+
+// PluginManager finds and runs external commands.
+type PluginManager struct {
+	cfg *config.Config
+}
+
+// NewPluginManager creates a new PluginManager instance.
+func NewPluginManager(cfg *config.Config) (*PluginManager, error) {
+	return &PluginManager{cfg: cfg}, nil
+}
+
+// BinDirs returns the bin directories of the plugins installed under
+// GOENV_ROOT/plugins.
+func (pm *PluginManager) BinDirs() []string {
+	dirs, _ := filepath.Glob(filepath.Join(pm.cfg.RootDir, constants.PluginsDir, "*", "bin"))
+	return dirs
+}
+
+// searchPath returns the directories searched for plugins: the plugin bin
+// directories, then PATH.
+func (pm *PluginManager) searchPath() []string {
+	return append(pm.BinDirs(), filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// List returns the available plugins sorted by name. When several
+// executables share a name, the first one in the search path wins.
+func (pm *PluginManager) List() []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+
+	for _, dir := range pm.searchPath() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), Prefix)
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !utils.IsExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// Exec replaces the current process with the plugin. The plugin bin
+// directories are put first in PATH and GOENV_ROOT, GOENV_DIR and, if it can
+// be determined, the resolved version are passed along. It only returns on
+// error.
+func (pm *PluginManager) Exec(plugin Plugin, args []string, resolvedVersion string) error {
+	env := []string{
+		constants.EnvGoenvRootDir + "=" + pm.cfg.RootDir,
+		"PATH=" + strings.Join(append(pm.BinDirs(), os.Getenv("PATH")), string(os.PathListSeparator)),
+	}
+
+	dir := pm.cfg.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	env = append(env, constants.EnvGoenvDir+"="+dir)
+
+	if resolvedVersion != "" {
+		env = append(env, EnvResolvedVersion+"="+resolvedVersion)
+	}
+
+	argv := append([]string{plugin.Path}, args...)
+	return syscall.Exec(plugin.Path, argv, utils.SetEnv(os.Environ(), env...))
+}
+
+// Summary returns the `# Summary:` comment of a plugin script, as shown by
+// the bash goenv help.
+func (p Plugin) Summary() string {
+	f, err := os.Open(p.Path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < summaryScanLimit && scanner.Scan(); i++ {
+		if summary, ok := strings.CutPrefix(scanner.Text(), "# Summary:"); ok {
+			return strings.TrimSpace(summary)
+		}
+	}
+
+	return ""
+}
+
+// SupportsCompletion reports whether the plugin lists completions when run
+// with --complete, like the bash goenv commands do.
+func (p Plugin) SupportsCompletion() bool {
+	content, err := os.ReadFile(p.Path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), "# Provide goenv completions")
+}
@@ -0,0 +1,76 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// writePlugin creates a plugin script at path with the given mode.
+func writePlugin(t *testing.T, path, script string, mode os.FileMode) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestList(t *testing.T) {
+	cfg := &config.Config{RootDir: t.TempDir()}
+	pluginBin := filepath.Join(cfg.RootDir, constants.PluginsDir, "goenv-doctor", "bin")
+	pathDir := t.TempDir()
+
+	writePlugin(t, filepath.Join(pluginBin, "goenv-doctor"), "", 0755)
+	writePlugin(t, filepath.Join(pathDir, "goenv-doctor"), "", 0755)
+	writePlugin(t, filepath.Join(pathDir, "goenv-sh-hello"), "", 0755)
+	writePlugin(t, filepath.Join(pathDir, "goenv-notes"), "", 0644)
+	writePlugin(t, filepath.Join(pathDir, "goenv-"), "", 0755)
+	writePlugin(t, filepath.Join(pathDir, "gofmt"), "", 0755)
+	t.Setenv("PATH", pathDir)
+
+	pm, err := NewPluginManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Plugin{
+		{Name: "doctor", Path: filepath.Join(pluginBin, "goenv-doctor")},
+		{Name: "sh-hello", Path: filepath.Join(pathDir, "goenv-sh-hello")},
+	}
+	got := pm.List()
+	if len(got) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPluginSummaryAndCompletion(t *testing.T) {
+	dir := t.TempDir()
+	documented := Plugin{Name: "doctor", Path: filepath.Join(dir, "goenv-doctor")}
+	writePlugin(t, documented.Path, "#\n# Summary: Check the goenv setup\n#\n# Provide goenv completions\n", 0755)
+	bare := Plugin{Name: "bare", Path: filepath.Join(dir, "goenv-bare")}
+	writePlugin(t, bare.Path, "echo bare\n", 0755)
+
+	if got := documented.Summary(); got != "Check the goenv setup" {
+		t.Errorf("Summary() = %q, want %q", got, "Check the goenv setup")
+	}
+	if !documented.SupportsCompletion() {
+		t.Error("SupportsCompletion() = false, want true")
+	}
+	if got := bare.Summary(); got != "" {
+		t.Errorf("Summary() = %q, want none", got)
+	}
+	if bare.SupportsCompletion() {
+		t.Error("SupportsCompletion() = true, want false")
+	}
+}
@@ -0,0 +1,351 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/hooks"
+	"github.com/go-nv/goenv/internal/utils"
+	"github.com/go-nv/goenv/internal/versions"
+)
+
+// ErrCommandNotFound is returned when the selected version does not provide
+// a command.
+var ErrCommandNotFound = errors.New("command not found")
+
+// Runner runs executables with the selected Go version, in-process and
+// without going through the bash goenv scripts.
+type Runner struct {
+	cfg      *config.Config
+	vm       *versions.VersionManager
+	hooks    *hooks.HookManager
+	shimsDir string
+
+	// self is the running goenv binary, looked up on first use
+	self     os.FileInfo
+	selfDone bool
+}
+
+// NewRunner creates a new Runner instance.
+func NewRunner(cfg *config.Config) (*Runner, error) {
+	vm, err := versions.NewVersionManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hm, err := hooks.NewHookManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		cfg:      cfg,
+		vm:       vm,
+		hooks:    hm,
+		shimsDir: filepath.Clean(filepath.Join(cfg.RootDir, constants.ShimsDir)),
+	}, nil
+}
+
+// IsShim reports whether the goenv binary was invoked through a shim: arg0
+// is a path in the shims directory, or the bare name of a shim found through
+// PATH. Running goenv under any other name, e.g. a renamed build, does not
+// make it a shim.
+func IsShim(cfg *config.Config, arg0 string) bool {
+	shimsDir := filepath.Join(cfg.RootDir, constants.ShimsDir)
+	name := filepath.Base(arg0)
+	if name == constants.ProjectName || name == constants.PrototypeShimFile || strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	// The shims directory may be reached through a symlinked GOENV_ROOT
+	if strings.ContainsRune(arg0, filepath.Separator) {
+		dirInfo, err := os.Stat(filepath.Dir(arg0))
+		if err != nil {
+			return false
+		}
+		shimsInfo, err := os.Stat(shimsDir)
+		return err == nil && os.SameFile(dirInfo, shimsInfo)
+	}
+
+	_, err := os.Lstat(filepath.Join(shimsDir, name))
+	return err == nil
+}
+
+// Version returns the installed Go version selected for dir, and where it
+// was set. An empty dir uses GOENV_DIR or the current directory.
+func (r *Runner) Version(dir string) (string, *versions.Resolution, error) {
+	resolution, err := r.vm.Resolve(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	version, err := r.vm.InstalledVersion(resolution)
+	if err != nil {
+		return "", resolution, fmt.Errorf("%w (set by %s)", err, resolution.Origin)
+	}
+
+	return version, resolution, nil
+}
+
+// Which returns the path of command for version: the version's bin directory,
+// then the bin directory of its GOPATH unless GOENV_DISABLE_GOPATH is set. For
+// the system version, PATH is searched without the shims directory. The which
+// hooks may change the path through GOENV_COMMAND_PATH.
+func (r *Runner) Which(command, version string) (string, error) {
+	path := r.findCommand(command, version)
+
+	result, err := r.hooks.Run(hooks.Which,
+		constants.EnvGoenvVersion+"="+version,
+		hooks.EnvCommand+"="+command,
+		hooks.EnvCommandPath+"="+path,
+	)
+	if err != nil {
+		return "", err
+	}
+	if hookPath, ok := result.Lookup(hooks.EnvCommandPath); ok {
+		path = hookPath
+	}
+
+	if path == "" || !utils.IsExecutable(path) {
+		return "", fmt.Errorf("'%s' %w", command, ErrCommandNotFound)
+	}
+
+	return path, nil
+}
+
+// Whence returns the installed versions providing command, oldest first,
+// with the path of command in each.
+func (r *Runner) Whence(command string) ([]string, []string, error) {
+	installed, err := r.vm.ListVersions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var versions, paths []string
+	for _, version := range installed {
+		if path := r.findCommand(command, version); path != "" {
+			versions = append(versions, version)
+			paths = append(paths, path)
+		}
+	}
+
+	return versions, paths, nil
+}
+
+// findCommand returns the path of command for version, or an empty string.
+// Of several versions separated by colons, the first providing command wins,
+// as in the bash goenv-which.
+func (r *Runner) findCommand(command, version string) string {
+	for _, v := range strings.Split(version, ":") {
+		if path := r.findVersionCommand(command, v); path != "" {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// findVersionCommand returns the path of command for a single version, or an
+// empty string.
+func (r *Runner) findVersionCommand(command, version string) string {
+	if version == constants.GoSystemVersion {
+		path, _ := r.lookPath(command)
+		return path
+	}
+
+	binDirs := []string{filepath.Join(r.cfg.VersionsDir(), version, constants.VersionsBinDir)}
+	if !r.cfg.DisableGopath && r.cfg.GopathPrefix != "" {
+		binDirs = append(binDirs, filepath.Join(r.cfg.GopathPrefix, version, constants.VersionsBinDir))
+	}
+
+	for _, binDir := range binDirs {
+		path := filepath.Join(binDir, command)
+		if utils.IsExecutable(path) {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// lookPath searches PATH for command, skipping the shims directory and any
+// other link to the goenv binary so that a shim never resolves to itself.
+func (r *Runner) lookPath(command string) (string, bool) {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+		if filepath.Clean(dir) == r.shimsDir {
+			continue
+		}
+
+		path := filepath.Join(dir, command)
+		if utils.IsExecutable(path) && !r.isSelf(path) {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+// isSelf reports whether path is the running goenv binary.
+func (r *Runner) isSelf(path string) bool {
+	if !r.selfDone {
+		r.selfDone = true
+		if executable, err := os.Executable(); err == nil {
+			r.self, _ = os.Stat(executable)
+		}
+	}
+	if r.self == nil {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	return err == nil && os.SameFile(info, r.self)
+}
+
+// Environ returns the environment for running commandPath with version, as
+// set up by the bash goenv-exec: GOENV_VERSION is set so that commands run
+// through shims use the same version, GOROOT and GOPATH are set as returned
+// by VersionEnv, and the command's directory and GOROOT/bin are put first in
+// PATH.
+func (r *Runner) Environ(version, commandPath string) []string {
+	env := append([]string{constants.EnvGoenvVersion + "=" + version}, r.VersionEnv(version)...)
+
+	goroot := os.Getenv("GOROOT")
+	if primary := primaryVersion(version); primary != constants.GoSystemVersion && !r.cfg.DisableGoroot {
+		goroot = filepath.Join(r.cfg.VersionsDir(), primary)
+	}
+
+	path := []string{filepath.Dir(commandPath)}
+	if goroot != "" {
+		path = append(path, filepath.Join(goroot, constants.VersionsBinDir))
+	}
+	path = append(path, os.Getenv("PATH"))
+	env = append(env, "PATH="+strings.Join(path, string(os.PathListSeparator)))
+
+	return utils.SetEnv(os.Environ(), env...)
+}
+
+// VersionEnv returns the GOROOT and GOPATH variables for version as
+// `KEY=value` pairs: GOROOT points to the version unless
+// GOENV_DISABLE_GOROOT is set, and GOPATH is derived from
+// GOENV_GOPATH_PREFIX unless GOENV_DISABLE_GOPATH is set. Nothing is set for
+// the system version. Of several versions separated by colons, the first one
+// is used.
+func (r *Runner) VersionEnv(version string) []string {
+	version = primaryVersion(version)
+	if version == constants.GoSystemVersion {
+		return nil
+	}
+
+	var env []string
+	if !r.cfg.DisableGoroot {
+		env = append(env, "GOROOT="+filepath.Join(r.cfg.VersionsDir(), version))
+	}
+	if !r.cfg.DisableGopath {
+		env = append(env, "GOPATH="+r.gopath(version))
+	}
+
+	return env
+}
+
+// primaryVersion returns the first of several versions separated by colons.
+func primaryVersion(version string) string {
+	primary, _, _ := strings.Cut(version, ":")
+	return primary
+}
+
+// gopath returns the GOPATH for version: GOENV_GOPATH_PREFIX/<version>,
+// combined with an existing GOPATH when GOENV_APPEND_GOPATH or
+// GOENV_PREPEND_GOPATH is set.
+func (r *Runner) gopath(version string) string {
+	gopath := filepath.Join(r.cfg.GopathPrefix, version)
+
+	existing := os.Getenv("GOPATH")
+	switch {
+	case existing != "" && r.cfg.AppendGopath:
+		return gopath + string(os.PathListSeparator) + existing
+	case existing != "" && r.cfg.PrependGopath:
+		return existing + string(os.PathListSeparator) + gopath
+	default:
+		return gopath
+	}
+}
+
+// RunShim runs the executable a shim named program stands for, with the
+// version selected for the current directory. Like the bash shims, the
+// version of go commands is taken from the directory of their first file
+// argument, e.g. `go run ../tool/main.go`. It only returns on error.
+func (r *Runner) RunShim(program string, args []string) error {
+	dir := ""
+	if strings.HasPrefix(program, "go") {
+		dir = fileArgDir(args)
+	}
+
+	return r.Exec(program, args, dir)
+}
+
+// Exec replaces the current process with command run with the version
+// selected for dir. It only returns on error.
+func (r *Runner) Exec(command string, args []string, dir string) error {
+	version, _, err := r.Version(dir)
+	if err != nil {
+		return err
+	}
+
+	commandPath, err := r.Which(command, version)
+	if err != nil {
+		return err
+	}
+
+	// Exec hooks may change the environment, including GOENV_COMMAND_PATH
+	result, err := r.hooks.Run(hooks.Exec,
+		constants.EnvGoenvVersion+"="+version,
+		hooks.EnvCommand+"="+command,
+		hooks.EnvCommandPath+"="+commandPath,
+	)
+	if err != nil {
+		return err
+	}
+	if hookPath, ok := result.Lookup(hooks.EnvCommandPath); ok {
+		commandPath = hookPath
+	}
+
+	r.cfg.Debugf("exec %s (%s)", commandPath, version)
+
+	argv := append([]string{command}, args...)
+	return syscall.Exec(commandPath, argv, result.Apply(r.Environ(version, commandPath)))
+}
+
+// fileArgDir returns the directory of the first argument naming an existing
+// file by path, stopping at `-c` flags and `--`.
+func fileArgDir(args []string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-c") || arg == "--" {
+			break
+		}
+		if !strings.Contains(arg, "/") {
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		// A symlinked file selects the version of its target's directory
+		if resolved, err := filepath.EvalSymlinks(arg); err == nil {
+			arg = resolved
+		}
+		return filepath.Dir(arg)
+	}
+
+	return ""
+}
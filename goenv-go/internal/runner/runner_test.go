@@ -0,0 +1,193 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// envShimTest makes the test binary act as goenv run through a shim.
+const envShimTest = "GOENV_RUNNER_SHIM_TEST"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envShimTest) == "1" {
+		cfg, err := config.Load()
+		if err == nil {
+			var r *Runner
+			if r, err = NewRunner(cfg); err == nil {
+				err = r.RunShim(filepath.Base(os.Args[0]), os.Args[1:])
+			}
+		}
+		fmt.Fprintf(os.Stderr, "goenv: %s\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
+
+// writeExecutable creates an executable shell script at path.
+func writeExecutable(t testing.TB, path, script string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsShim(t *testing.T) {
+	cfg := &config.Config{RootDir: t.TempDir()}
+	shimsDir := filepath.Join(cfg.RootDir, constants.ShimsDir)
+	writeExecutable(t, filepath.Join(shimsDir, "go"), "")
+	writeExecutable(t, filepath.Join(shimsDir, constants.PrototypeShimFile), "")
+
+	tests := []struct {
+		arg0 string
+		want bool
+	}{
+		{arg0: "go", want: true},
+		{arg0: filepath.Join(shimsDir, "go"), want: true},
+		{arg0: filepath.Join(shimsDir, "gofmt"), want: true},
+		{arg0: "goenv", want: false},
+		{arg0: "/usr/local/bin/goenv", want: false},
+		{arg0: "goenv-dev", want: false},
+		{arg0: "/opt/goenv/bin/go", want: false},
+		{arg0: "gofmt", want: false},
+		{arg0: constants.PrototypeShimFile, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg0, func(t *testing.T) {
+			if got := IsShim(cfg, tt.arg0); got != tt.want {
+				t.Errorf("IsShim(%q) = %t, want %t", tt.arg0, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookPathSkipsGoenv(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Skipf("cannot locate the test binary: %v", err)
+	}
+
+	cfg := &config.Config{RootDir: t.TempDir()}
+	r, err := NewRunner(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A link to goenv outside the shims directory, e.g. in a package
+	// manager's bin directory, must not be taken for the system command
+	linkDir := t.TempDir()
+	if err := os.Symlink(self, filepath.Join(linkDir, "tool")); err != nil {
+		t.Fatal(err)
+	}
+	systemDir := t.TempDir()
+	writeExecutable(t, filepath.Join(systemDir, "tool"), "")
+	shimsDir := filepath.Join(cfg.RootDir, constants.ShimsDir)
+	writeExecutable(t, filepath.Join(shimsDir, "tool"), "")
+
+	t.Setenv("PATH", shimsDir+string(os.PathListSeparator)+linkDir+string(os.PathListSeparator)+systemDir)
+
+	path, ok := r.lookPath("tool")
+	if want := filepath.Join(systemDir, "tool"); !ok || path != want {
+		t.Errorf("lookPath() = %q, %t, want %q", path, ok, want)
+	}
+}
+
+// bashGoenv is the bash goenv of this repository, whose shims the Go shims
+// replace.
+const bashGoenv = "../../../libexec/goenv"
+
+// BenchmarkShimDispatch compares running a command through a shim, which
+// resolves the version and execs the command, with running it directly and
+// through a shim of the bash goenv.
+func BenchmarkShimDispatch(b *testing.B) {
+	self, err := os.Executable()
+	if err != nil {
+		b.Skipf("cannot locate the test binary: %v", err)
+	}
+
+	root := b.TempDir()
+	command := filepath.Join(root, constants.VersionsDir, "1.22.0", constants.VersionsBinDir, "hello")
+	writeExecutable(b, command, "exit 0")
+
+	shim := filepath.Join(root, constants.ShimsDir, "hello")
+	if err := os.MkdirAll(filepath.Dir(shim), 0755); err != nil {
+		b.Fatal(err)
+	}
+	if err := os.Symlink(self, shim); err != nil {
+		b.Fatal(err)
+	}
+
+	env := append(os.Environ(),
+		envShimTest+"=1",
+		constants.EnvGoenvRootDir+"="+root,
+		constants.EnvGoenvRcFile+"="+filepath.Join(root, "goenvrc"),
+		constants.EnvGoenvVersion+"=1.22.0",
+	)
+
+	run := func(b *testing.B, path string) {
+		for i := 0; i < b.N; i++ {
+			cmd := exec.Command(path)
+			cmd.Env = env
+			if out, err := cmd.CombinedOutput(); err != nil {
+				b.Fatalf("%s: %v\n%s", path, err, out)
+			}
+		}
+	}
+
+	b.Run("direct", func(b *testing.B) { run(b, command) })
+	b.Run("shim", func(b *testing.B) { run(b, shim) })
+	b.Run("bash-shim", func(b *testing.B) {
+		goenv, err := filepath.Abs(bashGoenv)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := os.Stat(goenv); err != nil {
+			b.Skipf("bash goenv not found: %v", err)
+		}
+		if _, err := exec.LookPath("bash"); err != nil {
+			b.Skip("bash not found")
+		}
+
+		// The shim written by the bash goenv-rehash, minus the GOENV_FILE_ARG
+		// handling that does not apply here
+		bashShim := filepath.Join(b.TempDir(), "hello")
+		script := "#!/usr/bin/env bash\nset -e\nprogram=\"${0##*/}\"\n" +
+			"export GOENV_ROOT=\"" + root + "\"\nexec \"" + goenv + "\" exec \"$program\" \"$@\"\n"
+		if err := os.WriteFile(bashShim, []byte(script), 0755); err != nil {
+			b.Fatal(err)
+		}
+		run(b, bashShim)
+	})
+}
+
+func TestFindCommandSeveralVersions(t *testing.T) {
+	cfg := &config.Config{RootDir: t.TempDir(), DisableGopath: true}
+	r, err := NewRunner(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gofmt := filepath.Join(cfg.VersionsDir(), "1.21.3", constants.VersionsBinDir, "gofmt")
+	writeExecutable(t, gofmt, "")
+	if err := os.MkdirAll(filepath.Join(cfg.VersionsDir(), "1.22.3", constants.VersionsBinDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := r.findCommand("gofmt", "1.22.3:1.21.3"); got != gofmt {
+		t.Errorf("findCommand() = %q, want %q from the second version", got, gofmt)
+	}
+	if env := r.VersionEnv("1.22.3:1.21.3"); len(env) != 1 || env[0] != "GOROOT="+filepath.Join(cfg.VersionsDir(), "1.22.3") {
+		t.Errorf("VersionEnv() = %q, want GOROOT of the first version", env)
+	}
+}
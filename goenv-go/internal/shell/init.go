@@ -0,0 +1,252 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// InitOptions configures the shell integration emitted by `goenv init -`.
+type InitOptions struct {
+	Shell    string
+	RootDir  string
+	NoRehash bool
+
+	// Commands are the goenv commands that change the shell's environment.
+	// The goenv shell function evaluates the output of `goenv sh-<command>`
+	// for them.
+	Commands []string
+}
+
+// initData is passed to the init templates.
+type initData struct {
+	InitOptions
+	Root string // RootDir, quoted for the shell
+}
+
+var funcs = template.FuncMap{
+	"join": strings.Join,
+	"nuList": func(items []string) string {
+		quoted := make([]string, len(items))
+		for i, item := range items {
+			quoted[i] = nuQuote(item)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	},
+}
+
+// posixInit is the integration for bash, zsh and ksh, matching the bash
+// goenv-init.
+var posixInit = template.Must(template.New(Bash).Funcs(funcs).Parse(`export GOENV_SHELL={{.Shell}}
+export GOENV_ROOT={{.Root}}
+if [ -z "${GOENV_RC_FILE:-}" ]; then
+  GOENV_RC_FILE="${HOME}/.goenvrc"
+fi
+if [ -e "${GOENV_RC_FILE:-}" ]; then
+  source "${GOENV_RC_FILE}"
+fi
+if [ "${PATH#*$GOENV_ROOT/shims}" = "${PATH}" ]; then
+  if [ "${GOENV_PATH_ORDER:-}" = "front" ] ; then
+    export PATH="${GOENV_ROOT}/shims:${PATH}"
+  else
+    export PATH="${PATH}:${GOENV_ROOT}/shims"
+  fi
+fi
+{{- if eq .Shell "bash" "zsh"}}
+source <(command goenv completion {{.Shell}} 2>/dev/null)
+{{- end}}
+{{- if not .NoRehash}}
+command goenv rehash 2>/dev/null
+{{- end}}
+{{if eq .Shell "ksh"}}function goenv {
+  typeset command{{else}}goenv() {
+  local command{{end}}
+  command="$1"
+  if [ "$#" -gt 0 ]; then
+    shift
+  fi
+
+  case "$command" in
+  {{join .Commands "|"}})
+    eval "$(command goenv "sh-$command" "$@")";;
+  *)
+    command goenv "$command" "$@";;
+  esac
+}
+{{- if not .NoRehash}}
+goenv rehash --only-manage-paths
+{{- end}}
+`))
+
+var fishInit = template.Must(template.New(Fish).Funcs(funcs).Parse(`set -gx GOENV_SHELL fish
+set -gx GOENV_ROOT {{.Root}}
+if test -z "$GOENV_RC_FILE"
+  set GOENV_RC_FILE $HOME/.goenvrc
+end
+if test -e $GOENV_RC_FILE
+  source $GOENV_RC_FILE
+end
+if not contains $GOENV_ROOT/shims $PATH
+  if test "$GOENV_PATH_ORDER" = "front"
+    set -gx PATH $GOENV_ROOT/shims $PATH
+  else
+    set -gx PATH $PATH $GOENV_ROOT/shims
+  end
+end
+command goenv completion fish 2>/dev/null | source
+{{- if not .NoRehash}}
+command goenv rehash 2>/dev/null
+{{- end}}
+function goenv
+  set command $argv[1]
+  set -e argv[1]
+
+  switch "$command"
+  case {{join .Commands " "}}
+    source (command goenv "sh-$command" $argv|psub)
+  case '*'
+    command goenv $command $argv
+  end
+end
+{{- if not .NoRehash}}
+goenv rehash --only-manage-paths
+{{- end}}
+`))
+
+// nushellInit cannot source the POSIX configuration file or evaluate
+// generated code, so it parses the `KEY=value` lines itself and the goenv
+// function loads the JSON printed by the sh- commands.
+var nushellInit = template.Must(template.New(Nushell).Funcs(funcs).Parse(`$env.GOENV_SHELL = "nu"
+$env.GOENV_ROOT = {{.Root}}
+let goenv_rc = ($env.GOENV_RC_FILE? | default ($nu.home-path | path join ".goenvrc"))
+if ($goenv_rc | path exists) {
+  load-env (open --raw $goenv_rc
+    | lines
+    | each {|line| $line | str trim | str replace -r '^export\s+' '' }
+    | where {|line| ($line | str contains "=") and not ($line | str starts-with "#") }
+    | parse "{key}={value}"
+    | reduce -f {} {|it, acc| $acc | upsert ($it.key | str trim) ($it.value | str trim | str trim -c '"' | str trim -c "'") })
+}
+let goenv_shims = ($env.GOENV_ROOT | path join "shims")
+if not ($goenv_shims in $env.PATH) {
+  if ($env.GOENV_PATH_ORDER? == "front") {
+    $env.PATH = ($env.PATH | prepend $goenv_shims)
+  } else {
+    $env.PATH = ($env.PATH | append $goenv_shims)
+  }
+}
+{{- if not .NoRehash}}
+do -i { ^goenv rehash err> /dev/null }
+{{- end}}
+def --env --wrapped goenv [command?: string, ...args] {
+  if $command == null {
+    ^goenv
+  } else if $command in {{nuList .Commands}} {
+    let changes = (^goenv $"sh-($command)" ...$args | from json)
+    load-env $changes.set
+    hide-env -i ...$changes.unset
+  } else {
+    ^goenv $command ...$args
+  }
+}
+{{- if not .NoRehash}}
+goenv rehash --only-manage-paths
+{{- end}}
+`))
+
+var elvishInit = template.Must(template.New(Elvish).Funcs(funcs).Parse(`use path
+use str
+set-env GOENV_SHELL elvish
+set-env GOENV_ROOT {{.Root}}
+var goenv-rc = $E:HOME/.goenvrc
+if (has-env GOENV_RC_FILE) {
+  set goenv-rc = $E:GOENV_RC_FILE
+}
+if (path:is-regular $goenv-rc) {
+  for line [(from-lines < $goenv-rc)] {
+    set line = (str:trim-prefix (str:trim-space $line) 'export ')
+    if (and (str:contains $line =) (not (str:has-prefix $line '#'))) {
+      var kv = [(str:split &max=2 = $line)]
+      set-env (str:trim-space $kv[0]) (str:trim (str:trim-space $kv[1]) "\"'")
+    }
+  }
+}
+var goenv-shims = $E:GOENV_ROOT/shims
+if (not (has-value $paths $goenv-shims)) {
+  if (eq $E:GOENV_PATH_ORDER front) {
+    set paths = [$goenv-shims $@paths]
+  } else {
+    set paths = [$@paths $goenv-shims]
+  }
+}
+{{- if not .NoRehash}}
+try { e:goenv rehash 2>/dev/null } catch { }
+{{- end}}
+fn goenv {|@args|
+  if (and (> (count $args) 0) (has-value [{{join .Commands " "}}] $args[0])) {
+    eval (e:goenv sh-$args[0] $@args[1..] | slurp)
+  } else {
+    e:goenv $@args
+  }
+}
+edit:add-var goenv~ $goenv~
+{{- if not .NoRehash}}
+goenv rehash --only-manage-paths
+{{- end}}
+`))
+
+// Init returns the shell integration code evaluated by `eval "$(goenv init -)"`
+// and its equivalents.
+func Init(opts InitOptions) (string, error) {
+	data := initData{InitOptions: opts}
+
+	var tmpl *template.Template
+	switch opts.Shell {
+	case Bash, Zsh, Ksh:
+		tmpl, data.Root = posixInit, Quote(opts.RootDir)
+	case Fish:
+		tmpl, data.Root = fishInit, fishQuote(opts.RootDir)
+	case Nushell:
+		tmpl, data.Root = nushellInit, nuQuote(opts.RootDir)
+	case Elvish:
+		tmpl, data.Root = elvishInit, elvishQuote(opts.RootDir)
+	default:
+		return "", fmt.Errorf("unsupported shell %q, expected one of %s", opts.Shell, strings.Join(Shells, ", "))
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// Profile returns the file the integration is loaded from for shell, and
+// the code to add to it. bashrc selects ~/.bashrc over ~/.bash_profile.
+func Profile(shell string, bashrc bool) (string, string) {
+	switch shell {
+	case Bash:
+		if bashrc {
+			return "~/.bashrc", `eval "$(goenv init -)"`
+		}
+		return "~/.bash_profile", `eval "$(goenv init -)"`
+	case Zsh:
+		return "~/.zshrc", `eval "$(goenv init -)"`
+	case Ksh:
+		return "~/.profile", `eval "$(goenv init -)"`
+	case Fish:
+		return "~/.config/fish/config.fish", "status --is-interactive; and source (goenv init -|psub)"
+	case Nushell:
+		// Nushell only sources files known when config.nu is parsed
+		return "~/.config/nushell/env.nu", `goenv init - nu | save -f ($nu.default-config-dir | path join "goenv.nu")
+
+# and the following to ~/.config/nushell/config.nu:
+
+source ($nu.default-config-dir | path join "goenv.nu")`
+	case Elvish:
+		return "~/.config/elvish/rc.elv", "eval (goenv init - elvish | slurp)"
+	default:
+		return fmt.Sprintf("<unknown shell: %s, replace with your profile path>", shell), `eval "$(goenv init -)"`
+	}
+}
@@ -0,0 +1,64 @@
+package shell
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestInitGolden(t *testing.T) {
+	for _, sh := range Shells {
+		t.Run(sh, func(t *testing.T) {
+			got, err := Init(InitOptions{
+				Shell:    sh,
+				RootDir:  "/home/gopher/.goenv root",
+				Commands: []string{"rehash", "shell"},
+			})
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+
+			golden := filepath.Join("testdata", "init."+sh+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("Init() output differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestInitNoRehash(t *testing.T) {
+	for _, sh := range Shells {
+		t.Run(sh, func(t *testing.T) {
+			withRehash, err := Init(InitOptions{Shell: sh, RootDir: "/root/.goenv"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			withoutRehash, err := Init(InitOptions{Shell: sh, RootDir: "/root/.goenv", NoRehash: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if withRehash == withoutRehash {
+				t.Error("--no-rehash does not change the output")
+			}
+		})
+	}
+}
+
+func TestInitUnsupported(t *testing.T) {
+	if _, err := Init(InitOptions{Shell: "tcsh"}); err == nil {
+		t.Error("Init(tcsh) succeeded, want error")
+	}
+}
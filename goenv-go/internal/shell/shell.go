@@ -0,0 +1,136 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Supported shells, named after their executables.
+const (
+	Bash    = "bash"
+	Zsh     = "zsh"
+	Ksh     = "ksh"
+	Fish    = "fish"
+	Nushell = "nu"
+	Elvish  = "elvish"
+)
+
+// Shells lists the supported shells.
+var Shells = []string{Bash, Zsh, Ksh, Fish, Nushell, Elvish}
+
+// IsSupported reports whether goenv can generate code for shell.
+func IsSupported(shell string) bool {
+	for _, s := range Shells {
+		if s == shell {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the name of the shell goenv was run from, looking at the
+// parent process like the bash goenv-init and falling back to SHELL.
+func Detect() string {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(os.Getppid()), "-o", "args=").Output()
+	if err == nil {
+		if fields := strings.Fields(string(out)); len(fields) > 0 {
+			return name(fields[0])
+		}
+	}
+
+	return name(os.Getenv("SHELL"))
+}
+
+// Current returns the shell the integration was set up for, goenvShell as
+// read from GOENV_SHELL, falling back to SHELL.
+func Current(goenvShell string) string {
+	if goenvShell != "" {
+		return name(goenvShell)
+	}
+	return name(os.Getenv("SHELL"))
+}
+
+// name returns the shell name of a command such as `-bash` (a login shell)
+// or `/usr/bin/zsh`.
+func name(command string) string {
+	return filepath.Base(strings.TrimPrefix(command, "-"))
+}
+
+// EnvScript returns code that sets and unsets environment variables in
+// shell. set holds `KEY=value` pairs. For nushell, which cannot evaluate
+// generated code, it is a JSON object loaded by the goenv function defined
+// by `goenv init`.
+func EnvScript(shell string, set []string, unset []string) string {
+	if shell == Nushell {
+		values := make(map[string]string, len(set))
+		for _, pair := range set {
+			key, value, _ := strings.Cut(pair, "=")
+			values[key] = value
+		}
+		if unset == nil {
+			unset = []string{}
+		}
+
+		out, _ := json.Marshal(struct {
+			Set   map[string]string `json:"set"`
+			Unset []string          `json:"unset"`
+		}{values, unset})
+		return string(out) + "\n"
+	}
+
+	var b strings.Builder
+	for _, pair := range set {
+		key, value, _ := strings.Cut(pair, "=")
+		switch shell {
+		case Fish:
+			fmt.Fprintf(&b, "set -gx %s %s\n", key, fishQuote(value))
+		case Elvish:
+			fmt.Fprintf(&b, "set-env %s %s\n", key, elvishQuote(value))
+		default:
+			fmt.Fprintf(&b, "export %s=%s\n", key, Quote(value))
+		}
+	}
+	for _, key := range unset {
+		switch shell {
+		case Fish:
+			fmt.Fprintf(&b, "set -e %s\n", key)
+		case Elvish:
+			fmt.Fprintf(&b, "unset-env %s\n", key)
+		default:
+			fmt.Fprintf(&b, "unset %s\n", key)
+		}
+	}
+
+	return b.String()
+}
+
+// Quote quotes s as a single word for POSIX shells.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s as a single word for fish.
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// elvishQuote quotes s as a single word for elvish.
+func elvishQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// nuQuote quotes s as a string for nushell, whose double-quoted strings
+// accept the escapes of JSON strings other than \u.
+func nuQuote(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,218 @@
+package shims
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/hooks"
+	"github.com/go-nv/goenv/internal/utils"
+)
+
+// ShimManager maintains the shims directory.
+type ShimManager struct {
+	cfg      *config.Config
+	hooks    *hooks.HookManager
+	shimsDir string
+}
+
+// NewShimManager creates a new ShimManager instance.
+func NewShimManager(cfg *config.Config) (*ShimManager, error) {
+	hm, err := hooks.NewHookManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShimManager{
+		cfg:      cfg,
+		hooks:    hm,
+		shimsDir: filepath.Join(cfg.RootDir, constants.ShimsDir),
+	}, nil
+}
+
+// Dir returns the shims directory.
+func (sm *ShimManager) Dir() string {
+	return sm.shimsDir
+}
+
+// Rehash writes a shim for every executable of the installed versions and
+// removes the shims of executables that no longer exist. Shims are hard links
+// to the goenv binary, or symbolic links where hard links are not possible,
+// which dispatches on the name it was invoked under. The prototype shim file
+// serves as a lock, so that concurrent rehashes, including those of the bash
+// goenv, do not interfere.
+func (sm *ShimManager) Rehash() error {
+	if err := os.MkdirAll(sm.shimsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create shims directory: %w", err)
+	}
+
+	prototypePath := filepath.Join(sm.shimsDir, constants.PrototypeShimFile)
+	lock, err := os.OpenFile(prototypePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0755)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("cannot rehash: %s exists (rehash in progress or interrupted via SIGKILL)", prototypePath)
+		}
+		return fmt.Errorf("cannot rehash: %s isn't writable", sm.shimsDir)
+	}
+	lock.Close()
+	defer os.Remove(prototypePath)
+
+	goenvPath, err := goenvExecutable()
+	if err != nil {
+		return err
+	}
+
+	names, err := sm.executableNames()
+	if err != nil {
+		return err
+	}
+
+	// Rehash hooks may register more shims, e.g. for plugins
+	result, err := sm.hooks.Run(hooks.Rehash)
+	if err != nil {
+		return err
+	}
+	if registered, ok := result.Lookup(hooks.EnvRegisteredShims); ok {
+		for _, name := range strings.Fields(registered) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	registered := make(map[string]bool, len(names))
+	for _, name := range names {
+		if err := sm.installShim(name, goenvPath); err != nil {
+			return err
+		}
+		registered[name] = true
+	}
+
+	return sm.removeStaleShims(registered)
+}
+
+// goenvExecutable returns the path of the running goenv binary.
+func goenvExecutable() (string, error) {
+	path, err := os.Executable()
+	if err == nil {
+		path, err = filepath.EvalSymlinks(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to locate goenv executable: %w", err)
+	}
+
+	return path, nil
+}
+
+// executableNames returns the sorted names of the executables in the bin
+// directory of every installed version and, unless GOENV_DISABLE_GOPATH is
+// set, of its GOPATH.
+func (sm *ShimManager) executableNames() ([]string, error) {
+	entries, err := os.ReadDir(sm.cfg.VersionsDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read versions directory: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		binDirs := []string{filepath.Join(sm.cfg.VersionsDir(), entry.Name(), constants.VersionsBinDir)}
+		if !sm.cfg.DisableGopath && sm.cfg.GopathPrefix != "" {
+			binDirs = append(binDirs, filepath.Join(sm.cfg.GopathPrefix, entry.Name(), constants.VersionsBinDir))
+		}
+
+		for _, binDir := range binDirs {
+			files, err := os.ReadDir(binDir)
+			if err != nil {
+				continue
+			}
+			for _, file := range files {
+				if utils.IsExecutable(filepath.Join(binDir, file.Name())) {
+					seen[file.Name()] = true
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// installShim links the shim for name to goenvPath unless it already points
+// to it. The link is created under a temporary name first and renamed into
+// place, replacing any older shim atomically.
+func (sm *ShimManager) installShim(name, goenvPath string) error {
+	shimPath := filepath.Join(sm.shimsDir, name)
+	if isSameFile(shimPath, goenvPath) {
+		return nil
+	}
+
+	tmpPath := filepath.Join(sm.shimsDir, "."+name+".tmp")
+	os.Remove(tmpPath)
+	if err := os.Link(goenvPath, tmpPath); err != nil {
+		// e.g. the goenv binary lives on another filesystem
+		if err := os.Symlink(goenvPath, tmpPath); err != nil {
+			return fmt.Errorf("failed to create shim %s: %w", name, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, shimPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to install shim %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// removeStaleShims removes the shims that are not registered, along with
+// temporary files left behind by an interrupted rehash.
+func (sm *ShimManager) removeStaleShims(registered map[string]bool) error {
+	entries, err := os.ReadDir(sm.shimsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read shims directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == constants.PrototypeShimFile || registered[name] {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasSuffix(name, ".tmp") {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(sm.shimsDir, name)); err != nil {
+			return fmt.Errorf("failed to remove stale shim %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isSameFile reports whether a and b exist and are the same file.
+func isSameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,145 @@
+package shims
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// newTestManager returns a ShimManager for a temporary root with a version
+// 1.22.3 providing go and gofmt.
+func newTestManager(t *testing.T) *ShimManager {
+	t.Helper()
+
+	cfg := &config.Config{RootDir: t.TempDir(), DisableGopath: true}
+	binDir := filepath.Join(cfg.VersionsDir(), "1.22.3", constants.VersionsBinDir)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go", "gofmt"} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sm, err := NewShimManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
+
+// shimNames returns the names in the shims directory.
+func shimNames(t *testing.T, sm *ShimManager) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(sm.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestRehashConcurrent(t *testing.T) {
+	sm := newTestManager(t)
+
+	// A rehash hook keeps the first rehash holding the lock until released
+	hookDir := t.TempDir()
+	sm.cfg.HookPath = []string{hookDir}
+	started := filepath.Join(t.TempDir(), "started")
+	release := filepath.Join(t.TempDir(), "release")
+	t.Setenv("GOENV_TEST_STARTED", started)
+	t.Setenv("GOENV_TEST_RELEASE", release)
+	script := "#!/bin/sh\ntouch \"$GOENV_TEST_STARTED\"\nwhile [ ! -e \"$GOENV_TEST_RELEASE\" ]; do sleep 0.01; done\n"
+	if err := os.MkdirAll(filepath.Join(hookDir, "rehash"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hookDir, "rehash", "wait"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	first := make(chan error, 1)
+	go func() { first <- sm.Rehash() }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first Rehash() did not reach its hook")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := sm.Rehash()
+	if err == nil || !strings.Contains(err.Error(), "exists") {
+		t.Errorf("second Rehash() error = %v, want the lock to be held", err)
+	}
+
+	if err := os.WriteFile(release, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-first; err != nil {
+		t.Fatalf("first Rehash() error = %v", err)
+	}
+
+	if got := strings.Join(shimNames(t, sm), " "); got != "go gofmt" {
+		t.Errorf("shims = %s, want go gofmt and no lock left behind", got)
+	}
+}
+
+func TestRehashRemovesStaleShims(t *testing.T) {
+	sm := newTestManager(t)
+	if err := os.MkdirAll(sm.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"godoc", ".go.tmp", ".keep"} {
+		if err := os.WriteFile(filepath.Join(sm.Dir(), name), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sm.Rehash(); err != nil {
+		t.Fatalf("Rehash() error = %v", err)
+	}
+
+	if got := strings.Join(shimNames(t, sm), " "); got != ".keep go gofmt" {
+		t.Errorf("shims = %s, want .keep go gofmt", got)
+	}
+}
+
+func TestRehashReplacesShim(t *testing.T) {
+	sm := newTestManager(t)
+	if err := os.MkdirAll(sm.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	shimPath := filepath.Join(sm.Dir(), "go")
+	if err := os.WriteFile(shimPath, []byte("#!/bin/sh\nexec goenv exec go \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sm.Rehash(); err != nil {
+		t.Fatalf("Rehash() error = %v", err)
+	}
+
+	goenvPath, err := goenvExecutable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSameFile(shimPath, goenvPath) {
+		t.Errorf("shim %s was not replaced by a link to %s", shimPath, goenvPath)
+	}
+	if _, err := os.Lstat(filepath.Join(sm.Dir(), ".go.tmp")); !os.IsNotExist(err) {
+		t.Errorf("temporary shim left behind: %v", err)
+	}
+}
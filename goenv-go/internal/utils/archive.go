@@ -0,0 +1,373 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReadArchiveFile returns the contents of the file called name inside a
+// tar.gz archive.
+func ReadArchiveFile(archivePath, name string) ([]byte, error) {
+	var content []byte
+	found, err := walkArchive(archivePath, func(header *tar.Header, r io.Reader) (bool, error) {
+		if header.Typeflag != tar.TypeReg || strings.TrimPrefix(header.Name, "./") != name {
+			return false, nil
+		}
+
+		var err error
+		content, err = io.ReadAll(r)
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s not found in archive", name)
+	}
+
+	return content, nil
+}
+
+// FindArchiveEntry returns the name of the first entry of a tar.gz archive
+// for which match returns true, and false if there is none.
+func FindArchiveEntry(archivePath string, match func(name string) bool) (string, bool, error) {
+	var name string
+	found, err := walkArchive(archivePath, func(header *tar.Header, _ io.Reader) (bool, error) {
+		name = strings.TrimPrefix(header.Name, "./")
+		return match(name), nil
+	})
+	if err != nil || !found {
+		return "", false, err
+	}
+
+	return name, true, nil
+}
+
+// walkArchive calls fn for each entry of a tar.gz archive until it returns
+// true or an error, and reports whether it returned true.
+func walkArchive(archivePath string, fn func(header *tar.Header, r io.Reader) (bool, error)) (bool, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return false, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		if done, err := fn(header, tr); done || err != nil {
+			return done, err
+		}
+	}
+}
+
+// ExtractArchive extracts a tar.gz archive to the specified directory.
+//
+// The top-level "go" directory of Go distributions is stripped. Entries that
+// would end up outside targetDir, either directly or through a symlink, are
+// rejected, as are symlinks and hard links pointing outside of it. Paths are
+// resolved one component at a time as the OS would follow them, so chains of
+// symlinks cannot escape either. Setuid and setgid bits are dropped and
+// modification times are preserved.
+func ExtractArchive(archivePath, targetDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+
+	// Create the target directory if it doesn't exist
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	// Resolve the target directory so that symlinked parents (e.g. /tmp on
+	// macOS) do not make every entry look like it escapes it.
+	targetDir, err = filepath.EvalSymlinks(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+
+	// Directory modification times are restored last, as extracting their
+	// contents updates them.
+	dirTimes := make(map[string]time.Time)
+
+	// Extract all files from the archive
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		// The Go distribution has a top-level "go" directory
+		// We need to strip this prefix and place files directly in the target directory
+		name := stripArchivePrefix(header.Name)
+		if name == "" {
+			// Skip the top-level directory entry
+			continue
+		}
+
+		if err := extractEntry(tr, header, name, targetDir, dirTimes); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+
+	for dir, mtime := range dirTimes {
+		if err := os.Chtimes(dir, mtime, mtime); err != nil {
+			return fmt.Errorf("failed to set modification time of %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// stripArchivePrefix removes the top-level "go" directory from an entry name.
+func stripArchivePrefix(name string) string {
+	name = strings.TrimPrefix(name, "./")
+	if name == "go" || name == "go/" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "go/")
+}
+
+// extractEntry extracts a single archive entry as name below targetDir.
+func extractEntry(r io.Reader, header *tar.Header, name, targetDir string, dirTimes map[string]time.Time) error {
+	target, err := archiveTarget(targetDir, name)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(targetDir, target)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(header.Mode).Perm()
+
+	if header.Typeflag == tar.TypeDir {
+		// Create the directory component-wise, refusing to follow symlinks
+		// that lead out of the target directory
+		dir, err := resolveWithin(targetDir, targetDir, rel, true)
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(dir, mode|0700); err != nil {
+			return fmt.Errorf("failed to set permissions: %w", err)
+		}
+		dirTimes[dir] = header.ModTime
+		return nil
+	}
+
+	// Refuse to write through a symlink that leads out of the target directory
+	parent, err := resolveWithin(targetDir, targetDir, filepath.Dir(rel), true)
+	if err != nil {
+		return err
+	}
+	target = filepath.Join(parent, filepath.Base(rel))
+
+	switch header.Typeflag {
+	case tar.TypeReg:
+		if err := prepareTarget(target); err != nil {
+			return err
+		}
+
+		// Create the file, never following whatever was at target before
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+
+		// Copy the file contents
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	case tar.TypeSymlink:
+		// Absolute links could only point inside the tree at its staging
+		// location, so they are rejected along with escaping relative ones.
+		// Relative links are resolved from the resolved parent, the way the
+		// OS follows them.
+		if filepath.IsAbs(header.Linkname) || strings.HasPrefix(header.Linkname, "/") {
+			return fmt.Errorf("symlink to %s points outside of the target directory", header.Linkname)
+		}
+		if _, err := resolveWithin(targetDir, parent, header.Linkname, false); err != nil {
+			return fmt.Errorf("symlink to %s: %w", header.Linkname, err)
+		}
+
+		if err := prepareTarget(target); err != nil {
+			return err
+		}
+		if err := os.Symlink(header.Linkname, target); err != nil {
+			return fmt.Errorf("failed to create symlink to %s: %w", header.Linkname, err)
+		}
+		return nil
+	case tar.TypeLink:
+		source, err := hardLinkSource(targetDir, stripArchivePrefix(header.Linkname))
+		if err != nil {
+			return fmt.Errorf("hard link to %s: %w", header.Linkname, err)
+		}
+
+		if err := prepareTarget(target); err != nil {
+			return err
+		}
+		if err := os.Link(source, target); err != nil {
+			return fmt.Errorf("failed to create hard link to %s: %w", header.Linkname, err)
+		}
+	default:
+		// Skip other types (e.g., character devices, fifos, etc.)
+		return nil
+	}
+
+	if err := os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+		return fmt.Errorf("failed to set modification time: %w", err)
+	}
+
+	return nil
+}
+
+// archiveTarget returns the path of name below targetDir, rejecting names that
+// are absolute or climb out of targetDir.
+func archiveTarget(targetDir, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) || strings.HasPrefix(name, "/") || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("invalid path %q", name)
+	}
+
+	target := filepath.Join(targetDir, filepath.FromSlash(name))
+	if !isWithin(targetDir, target) {
+		return "", fmt.Errorf("path %q escapes the target directory", name)
+	}
+
+	return target, nil
+}
+
+// hardLinkSource returns the resolved path of the hard link source name, an
+// already extracted regular file inside targetDir.
+func hardLinkSource(targetDir, name string) (string, error) {
+	if _, err := archiveTarget(targetDir, name); err != nil {
+		return "", err
+	}
+
+	source, err := resolveWithin(targetDir, targetDir, name, false)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil {
+		return "", fmt.Errorf("source not extracted: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("source %s is not a regular file", source)
+	}
+
+	return source, nil
+}
+
+// resolveWithin resolves the relative path rel from dir one component at a
+// time, following symlinks the way the OS would, and fails as soon as the
+// result leaves root. With create set, missing components are created as
+// directories. Otherwise the rest of the path is taken as written, and may
+// not climb out of a missing directory, since a symlink created there later
+// could redirect it.
+func resolveWithin(root, dir, rel string, create bool) (string, error) {
+	current := dir
+	missing := false
+
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if missing {
+				return "", fmt.Errorf("%q climbs out of a directory that does not exist", rel)
+			}
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, part)
+			if missing {
+				break
+			}
+
+			info, err := os.Lstat(current)
+			switch {
+			case os.IsNotExist(err) && create:
+				if err := os.Mkdir(current, 0755); err != nil {
+					return "", fmt.Errorf("failed to create directory: %w", err)
+				}
+			case os.IsNotExist(err):
+				missing = true
+			case err != nil:
+				return "", err
+			case info.Mode()&os.ModeSymlink != 0:
+				resolved, err := filepath.EvalSymlinks(current)
+				if err != nil {
+					return "", fmt.Errorf("failed to resolve %s: %w", current, err)
+				}
+				current = resolved
+			}
+		}
+
+		if !isWithin(root, current) {
+			return "", fmt.Errorf("%q resolves to %s, outside of the target directory", rel, current)
+		}
+	}
+
+	return current, nil
+}
+
+// prepareTarget removes an existing non-directory at target so that it is
+// replaced rather than written through.
+func prepareTarget(target string) error {
+	info, err := os.Lstat(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s already exists as a directory", target)
+	}
+	return os.Remove(target)
+}
+
+// isWithin reports whether path is dir or lies below it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+// writeArchive creates a tar.gz archive holding entries, in order.
+func writeArchive(t *testing.T, entries []archiveEntry) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "go.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     0644,
+			Size:     int64(len(entry.content)),
+		}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0755
+		}
+		if entry.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestExtractArchive(t *testing.T) {
+	entries := []archiveEntry{
+		{name: "go/", typeflag: tar.TypeDir},
+		{name: "go/VERSION", typeflag: tar.TypeReg, content: "go1.22.0\n"},
+		{name: "go/sub/deep/", typeflag: tar.TypeDir},
+		{name: "go/l1", typeflag: tar.TypeSymlink, linkname: "l2"},
+		{name: "go/l2", typeflag: tar.TypeSymlink, linkname: "sub/deep"},
+		{name: "go/l1/file", typeflag: tar.TypeReg, content: "through links"},
+		{name: "go/sub/up", typeflag: tar.TypeSymlink, linkname: "../VERSION"},
+		{name: "go/hard", typeflag: tar.TypeLink, linkname: "go/l1/file"},
+	}
+
+	targetDir := t.TempDir()
+	if err := ExtractArchive(writeArchive(t, entries), targetDir); err != nil {
+		t.Fatalf("ExtractArchive() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"VERSION":       "go1.22.0\n",
+		"sub/deep/file": "through links",
+		"sub/up":        "go1.22.0\n",
+		"hard":          "through links",
+	} {
+		got, err := os.ReadFile(filepath.Join(targetDir, name))
+		if err != nil {
+			t.Errorf("reading %s: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestExtractArchiveHostile(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		// existing is created in the target directory before extracting:
+		// a symlink to the outside directory
+		existing string
+	}{
+		{
+			name:    "dot-dot name",
+			entries: []archiveEntry{{name: "go/../../outside/evil", typeflag: tar.TypeReg, content: "x"}},
+		},
+		{
+			name:    "absolute name",
+			entries: []archiveEntry{{name: "/outside/evil", typeflag: tar.TypeReg, content: "x"}},
+		},
+		{
+			name:    "dot-dot symlink",
+			entries: []archiveEntry{{name: "go/link", typeflag: tar.TypeSymlink, linkname: "../.."}},
+		},
+		{
+			name:    "absolute symlink",
+			entries: []archiveEntry{{name: "go/link", typeflag: tar.TypeSymlink, linkname: "/"}},
+		},
+		{
+			name: "chained symlinks",
+			entries: []archiveEntry{
+				{name: "go/q/", typeflag: tar.TypeDir},
+				{name: "go/p/c", typeflag: tar.TypeSymlink, linkname: "../q"},
+				{name: "go/p/a", typeflag: tar.TypeSymlink, linkname: "c/../.."},
+			},
+		},
+		{
+			name: "symlink climbing out of a later symlink",
+			entries: []archiveEntry{
+				{name: "go/p/a", typeflag: tar.TypeSymlink, linkname: "c/../.."},
+				{name: "go/p/c", typeflag: tar.TypeSymlink, linkname: "../q"},
+			},
+		},
+		{
+			name:     "file through existing symlink",
+			existing: "out",
+			entries:  []archiveEntry{{name: "go/out/evil", typeflag: tar.TypeReg, content: "x"}},
+		},
+		{
+			name:     "directory through existing symlink",
+			existing: "out",
+			entries:  []archiveEntry{{name: "go/out/evil/", typeflag: tar.TypeDir}},
+		},
+		{
+			name:    "dot-dot hard link",
+			entries: []archiveEntry{{name: "go/hard", typeflag: tar.TypeLink, linkname: "go/../../outside/secret"}},
+		},
+		{
+			name:     "hard link through symlink",
+			existing: "out",
+			entries:  []archiveEntry{{name: "go/hard", typeflag: tar.TypeLink, linkname: "go/out/secret"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			outside := filepath.Join(root, "outside")
+			targetDir := filepath.Join(root, "target", "go")
+			for _, dir := range []string{outside, targetDir} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing != "" {
+				if err := os.Symlink(outside, filepath.Join(targetDir, tt.existing)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := ExtractArchive(writeArchive(t, tt.entries), targetDir); err == nil {
+				t.Error("ExtractArchive() succeeded, want error")
+			}
+
+			leaked, err := os.ReadDir(outside)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(leaked) != 1 {
+				var names []string
+				for _, entry := range leaked {
+					names = append(names, entry.Name())
+				}
+				t.Errorf("outside directory holds %s, want only secret", strings.Join(names, ", "))
+			}
+		})
+	}
+}
@@ -1,12 +1,8 @@
 package utils
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -14,18 +10,8 @@ import (
 	"github.com/go-nv/goenv/internal/version"
 )
 
-func InitDirs() error {
-	goenvRootDir, err := GetGoenvRootDir()
-	if err != nil {
-		return fmt.Errorf("failed to get goenv root directory: %w", err)
-	}
-
-	dirs := []string{
-		constants.VersionsDir,
-		constants.GlobalGoVersionFile,
-		constants.LocalGoVersionFile,
-	}
-
+// InitDirs creates the goenv root directory and its versions directory.
+func InitDirs(goenvRootDir, versionsDir string) error {
 	// Ensure goenvRootDir exists, else create it
 	if _, err := os.Stat(goenvRootDir); os.IsNotExist(err) {
 		if err := os.MkdirAll(goenvRootDir, 0755); err != nil {
@@ -33,25 +19,26 @@ func InitDirs() error {
 		}
 	}
 
-	// Ensure all dirs exist, else create them
-	for _, dir := range dirs {
-		p := filepath.Join(goenvRootDir, dir)
-		if err := os.MkdirAll(p, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
-		}
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 	return nil
 }
 
-func GetGoenvRootDir() (string, error) {
-	// ToDo: @hibare handle GO_ENV_ROOT env variable
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+// FormatBytes formats a size in bytes using binary units, e.g. 68.2 MiB.
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
 
-	rootDir := filepath.Join(homeDir, constants.GoenvRootDir)
-	return rootDir, nil
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
 }
 
 func GetHTTPUserAgent() string {
@@ -66,81 +53,30 @@ func GetArch() string {
 	return runtime.GOARCH
 }
 
-// ExtractArchive extracts a tar.gz archive to the specified directory.
-func ExtractArchive(archivePath, targetDir string) error {
-	file, err := os.Open(archivePath)
-	if err != nil {
-		return fmt.Errorf("failed to open archive: %w", err)
-	}
-	defer file.Close()
-
-	gzr, err := gzip.NewReader(file)
-	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer gzr.Close()
-
-	tr := tar.NewReader(gzr)
-
-	// Create the target directory if it doesn't exist
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return fmt.Errorf("failed to create target directory: %w", err)
-	}
-
-	// Extract all files from the archive
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break // End of archive
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read tar header: %w", err)
-		}
-
-		// The Go distribution has a top-level "go" directory
-		// We need to strip this prefix and place files directly in the target directory
-		name := header.Name
-		if strings.HasPrefix(name, "go/") {
-			name = strings.TrimPrefix(name, "go/")
-		} else if name == "go" {
-			// Skip the top-level directory entry
-			continue
-		}
-
-		target := filepath.Join(targetDir, name)
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", target, err)
-			}
-		case tar.TypeReg:
-			// Create parent directories if they don't exist
-			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-				return fmt.Errorf("failed to create parent directory for %s: %w", target, err)
-			}
-
-			// Create the file
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-			if err != nil {
-				return fmt.Errorf("failed to create file %s: %w", target, err)
+// SetEnv returns env with the given `KEY=value` pairs set, replacing any
+// existing values.
+func SetEnv(env []string, pairs ...string) []string {
+	result := make([]string, 0, len(env)+len(pairs))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		replaced := false
+		for _, pair := range pairs {
+			if strings.HasPrefix(pair, key+"=") {
+				replaced = true
+				break
 			}
-
-			// Copy the file contents
-			if _, err := io.Copy(f, tr); err != nil {
-				f.Close()
-				return fmt.Errorf("failed to write file %s: %w", target, err)
-			}
-			f.Close()
-		case tar.TypeSymlink:
-			if err := os.Symlink(header.Linkname, target); err != nil {
-				return fmt.Errorf("failed to create symlink %s -> %s: %w", target, header.Linkname, err)
-			}
-		default:
-			// Skip other types (e.g., hardlinks, character devices, etc.)
-			continue
+		}
+		if !replaced {
+			result = append(result, kv)
 		}
 	}
 
-	return nil
+	return append(result, pairs...)
+}
+
+// IsExecutable reports whether path is a file, or a link to one, that is
+// executable by someone.
+func IsExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
 }
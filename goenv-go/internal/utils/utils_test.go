@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetEnv(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "GOENV_ROOT=/old", "GOENV_ROOTS=/other"}
+
+	got := SetEnv(env, "GOENV_ROOT=/new", "GOENV_DIR=/project")
+	want := []string{"PATH=/usr/bin", "GOENV_ROOTS=/other", "GOENV_ROOT=/new", "GOENV_DIR=/project"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SetEnv() = %q, want %q", got, want)
+	}
+}
+
+func TestIsExecutable(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "tool")
+	plain := filepath.Join(dir, "notes")
+	link := filepath.Join(dir, "link")
+	if err := os.WriteFile(executable, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(plain, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(executable, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{executable: true, link: true, plain: false, dir: false, filepath.Join(dir, "missing"): false}
+	for path, want := range tests {
+		if got := IsExecutable(path); got != want {
+			t.Errorf("IsExecutable(%s) = %t, want %t", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+)
+
+// aliasNamePattern restricts alias names to what is safe as a file name.
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// GetAliasesDir returns the directory holding the version aliases.
+func (vm *VersionManager) GetAliasesDir() string {
+	return filepath.Join(vm.rootDir, constants.AliasesDir)
+}
+
+// ValidateAliasName checks that name can be used as an alias: it must be a
+// plain name that cannot be mistaken for a version.
+func ValidateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+
+	switch name {
+	case constants.GoSystemVersion, constants.LatestVersion, constants.UnstableVersion:
+		return fmt.Errorf("alias name %q is reserved", name)
+	}
+
+	if goversion.IsValid(name) {
+		return fmt.Errorf("alias name %q is a version", name)
+	}
+
+	return nil
+}
+
+// Aliases returns all aliases and the versions they point at.
+func (vm *VersionManager) Aliases() (map[string]string, error) {
+	aliases := make(map[string]string)
+
+	files, err := os.ReadDir(vm.GetAliasesDir())
+	if os.IsNotExist(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases directory: %w", err)
+	}
+
+	for _, file := range files {
+		if !file.Type().IsRegular() || ValidateAliasName(file.Name()) != nil {
+			continue
+		}
+
+		version, err := vm.readVersionFile(filepath.Join(vm.GetAliasesDir(), file.Name()))
+		if err != nil {
+			continue
+		}
+		aliases[file.Name()] = version
+	}
+
+	return aliases, nil
+}
+
+// AliasNames returns the aliases pointing at each version, sorted by name.
+func (vm *VersionManager) AliasNames() (map[string][]string, error) {
+	aliases, err := vm.Aliases()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string][]string)
+	for name, version := range aliases {
+		names[version] = append(names[version], name)
+	}
+	for _, n := range names {
+		sort.Strings(n)
+	}
+
+	return names, nil
+}
+
+// ResolveAlias returns the version name points at, if name is an alias.
+func (vm *VersionManager) ResolveAlias(name string) (string, bool) {
+	if ValidateAliasName(name) != nil {
+		return "", false
+	}
+
+	version, err := vm.readVersionFile(filepath.Join(vm.GetAliasesDir(), name))
+	if err != nil {
+		return "", false
+	}
+
+	return version, true
+}
+
+// SetAlias points the alias name at version and returns the version stored.
+// Partial versions such as `1.22`, `latest` and constraints are pinned to the
+// release chosen by resolveVersion.
+func (vm *VersionManager) SetAlias(name, version string) (string, error) {
+	if err := ValidateAliasName(name); err != nil {
+		return "", err
+	}
+
+	version = strings.TrimSpace(version)
+	if target, ok := vm.ResolveAlias(version); ok {
+		version = target
+	}
+
+	if version != constants.GoSystemVersion && !vm.IsVersionInstalled(version) {
+		if v, err := goversion.Parse(version); err != nil || v.IsPartial() {
+			resolved, err := vm.resolveVersion(version)
+			if err != nil {
+				return "", err
+			}
+			version = resolved
+		}
+	}
+
+	if err := os.MkdirAll(vm.GetAliasesDir(), 0755); err != nil {
+		return "", fmt.Errorf("failed to create aliases directory: %w", err)
+	}
+
+	if err := vm.writeVersionFile(filepath.Join(vm.GetAliasesDir(), name), version); err != nil {
+		return "", fmt.Errorf("failed to write alias: %w", err)
+	}
+
+	return version, nil
+}
+
+// RemoveAlias removes the alias name.
+func (vm *VersionManager) RemoveAlias(name string) error {
+	if err := ValidateAliasName(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(vm.GetAliasesDir(), name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("alias %q does not exist", name)
+		}
+		return fmt.Errorf("failed to remove alias: %w", err)
+	}
+
+	return nil
+}
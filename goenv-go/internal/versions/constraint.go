@@ -0,0 +1,27 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/goversion"
+)
+
+// installedConstraintVersion returns the newest installed version satisfying
+// a version constraint such as `>=1.21 <1.23`.
+func (vm *VersionManager) installedConstraintVersion(spec string) (string, error) {
+	constraint, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", err
+	}
+
+	installed, _ := vm.ListVersions()
+	if version, ok := goversion.LatestSatisfying(installed, constraint); ok {
+		return version, nil
+	}
+
+	if len(installed) == 0 {
+		return "", fmt.Errorf("version satisfying '%s' is %w (no versions installed)", constraint, ErrNotInstalled)
+	}
+	return "", fmt.Errorf("version satisfying '%s' is %w (installed: %s)", constraint, ErrNotInstalled, strings.Join(installed, ", "))
+}
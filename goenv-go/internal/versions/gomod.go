@@ -0,0 +1,77 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+	"golang.org/x/mod/modfile"
+)
+
+// readGoModVersion reads the version required by a go.mod file. The
+// `toolchain` directive takes precedence over the `go` directive.
+func (vm *VersionManager) readGoModVersion(goModPath string) (string, error) {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", err
+	}
+
+	// ParseLax would skip the toolchain directive, which only applies to
+	// main modules.
+	file, err := modfile.Parse(goModPath, content, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", goModPath, err)
+	}
+
+	if file.Toolchain != nil {
+		if version, ok := strings.CutPrefix(file.Toolchain.Name, "go"); ok && goversion.IsValid(version) {
+			return version, nil
+		}
+	}
+
+	if file.Go != nil && file.Go.Version != "" {
+		return file.Go.Version, nil
+	}
+
+	return "", fmt.Errorf("no go version found in %s", goModPath)
+}
+
+// isGoModFile reports whether path is a go.mod file.
+func isGoModFile(path string) bool {
+	return filepath.Base(path) == constants.GoModFile
+}
+
+// installedGoModVersion returns the installed version satisfying a go.mod
+// requirement. The `go` and `toolchain` directives state a minimum, so the
+// newest installed release of the same minor line that is not older than
+// version is used, e.g. 1.21.5 for `go 1.21` or `go 1.21.0`.
+func (vm *VersionManager) installedGoModVersion(version string) (string, error) {
+	want, err := goversion.Parse(version)
+	if err != nil {
+		return "", err
+	}
+
+	installed, err := vm.ListVersions()
+	if err != nil {
+		return "", err
+	}
+
+	// ListVersions sorts oldest first, so the last match is the newest
+	var latest string
+	for _, name := range installed {
+		v, err := goversion.Parse(name)
+		if err != nil || v.Major != want.Major || v.Minor != want.Minor || v.Less(want) {
+			continue
+		}
+		latest = name
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("version '%s' is %w", version, ErrNotInstalled)
+	}
+
+	return latest, nil
+}
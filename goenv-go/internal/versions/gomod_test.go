@@ -0,0 +1,108 @@
+package versions
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadGoModVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		goMod   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "go directive",
+			goMod: "module m\n\ngo 1.22.0\n",
+			want:  "1.22.0",
+		},
+		{
+			name:  "partial go directive",
+			goMod: "module m\n\ngo 1.21\n",
+			want:  "1.21",
+		},
+		{
+			name:  "toolchain wins over go",
+			goMod: "module m\n\ngo 1.21\n\ntoolchain go1.22.3\n",
+			want:  "1.22.3",
+		},
+		{
+			name:  "custom toolchain falls back to go",
+			goMod: "module m\n\ngo 1.21.0\n\ntoolchain default\n",
+			want:  "1.21.0",
+		},
+		{
+			name:  "toolchain with suffix falls back to go",
+			goMod: "module m\n\ngo 1.21.0\n\ntoolchain go1.22.3+auto\n",
+			want:  "1.21.0",
+		},
+		{
+			name:    "no go directive",
+			goMod:   "module m\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid go.mod",
+			goMod:   "module m\n\ngo one\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, _ := newTestManager(t)
+
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"go.mod": tt.goMod})
+
+			got, err := vm.readGoModVersion(filepath.Join(dir, "go.mod"))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readGoModVersion() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readGoModVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readGoModVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstalledGoModVersion(t *testing.T) {
+	vm, _ := newTestManager(t, "1.20.14", "1.21.0", "1.21.5", "1.22.0")
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "1.21", want: "1.21.5"},
+		{version: "1.21.0", want: "1.21.5"},
+		{version: "1.22", want: "1.22.0"},
+		{version: "1.22.1", wantErr: true},
+		{version: "1.23", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := vm.installedGoModVersion(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("installedGoModVersion(%s) = %s, want error", tt.version, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("installedGoModVersion(%s) error = %v", tt.version, err)
+			}
+			if got != tt.want {
+				t.Errorf("installedGoModVersion(%s) = %s, want %s", tt.version, got, tt.want)
+			}
+		})
+	}
+}
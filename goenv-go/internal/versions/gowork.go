@@ -0,0 +1,114 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+	"golang.org/x/mod/modfile"
+)
+
+// isGoWorkFile reports whether path is a go.work file.
+func isGoWorkFile(path string) bool {
+	return filepath.Base(path) == constants.GoWorkFile
+}
+
+// findGoWorkFile returns the go.work file in dir, unless workspaces are
+// disabled or GOWORK names a specific file.
+func (vm *VersionManager) findGoWorkFile(dir string) (string, bool) {
+	if vm.cfg.GoWork != "" {
+		return "", false
+	}
+
+	goWorkPath := filepath.Join(dir, constants.GoWorkFile)
+	return goWorkPath, isRegularFile(goWorkPath)
+}
+
+// findEnclosingGoWorkFile returns the go.work file governing the module in
+// dir, following the go command: GOWORK if set, otherwise the closest go.work
+// in dir or its parents. GOWORK=off disables workspaces.
+func (vm *VersionManager) findEnclosingGoWorkFile(dir string) (string, bool) {
+	switch vm.cfg.GoWork {
+	case constants.GoWorkOff:
+		return "", false
+	case "":
+	default:
+		return vm.cfg.GoWork, isRegularFile(vm.cfg.GoWork)
+	}
+
+	for {
+		if goWorkPath, ok := vm.findGoWorkFile(dir); ok {
+			return goWorkPath, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// resolveGoWork determines the version required by a workspace. The
+// `toolchain` directive of go.work takes precedence; otherwise the highest of
+// its `go` directive and the requirements of every used module is chosen, and
+// the file that set it is reported as the origin.
+func (vm *VersionManager) resolveGoWork(goWorkPath string) (*Resolution, error) {
+	content, err := os.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := modfile.ParseWork(goWorkPath, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", goWorkPath, err)
+	}
+
+	if file.Toolchain != nil {
+		if version, ok := strings.CutPrefix(file.Toolchain.Name, "go"); ok && goversion.IsValid(version) {
+			return &Resolution{Version: version, Origin: goWorkPath, Workspace: goWorkPath}, nil
+		}
+	}
+
+	var best *Resolution
+	var bestVersion goversion.Version
+	consider := func(version, origin string) {
+		v, err := goversion.Parse(version)
+		if err != nil {
+			return
+		}
+		if best == nil || bestVersion.Less(v) {
+			best = &Resolution{Version: version, Origin: origin, Workspace: goWorkPath}
+			bestVersion = v
+		}
+	}
+
+	if file.Go != nil && file.Go.Version != "" {
+		consider(file.Go.Version, goWorkPath)
+	}
+
+	workDir := filepath.Dir(goWorkPath)
+	for _, use := range file.Use {
+		modDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(modDir) {
+			modDir = filepath.Join(workDir, modDir)
+		}
+
+		goModPath := filepath.Join(modDir, constants.GoModFile)
+		version, err := vm.readGoModVersion(goModPath)
+		if err != nil {
+			vm.cfg.Debugf("skipping workspace module %s: %s", modDir, err)
+			continue
+		}
+		consider(version, goModPath)
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no go version found in %s or its modules", goWorkPath)
+	}
+
+	return best, nil
+}
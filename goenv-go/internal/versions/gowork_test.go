@@ -0,0 +1,131 @@
+package versions
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+func TestResolveGoWork(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		want       string
+		wantOrigin string // Relative to the workspace directory
+		wantErr    bool
+	}{
+		{
+			name: "toolchain wins",
+			files: map[string]string{
+				"go.work":  "go 1.21.0\n\ntoolchain go1.22.3\n\nuse ./a\n",
+				"a/go.mod": "module a\n\ngo 1.23.0\n",
+			},
+			want:       "1.22.3",
+			wantOrigin: "go.work",
+		},
+		{
+			name: "highest module requirement",
+			files: map[string]string{
+				"go.work":  "go 1.21.0\n\nuse (\n\t./a\n\t./b\n)\n",
+				"a/go.mod": "module a\n\ngo 1.22.0\n",
+				"b/go.mod": "module b\n\ngo 1.21.5\n",
+			},
+			want:       "1.22.0",
+			wantOrigin: "a/go.mod",
+		},
+		{
+			name: "module toolchain counts",
+			files: map[string]string{
+				"go.work":  "go 1.21.0\n\nuse ./a\n",
+				"a/go.mod": "module a\n\ngo 1.21.0\n\ntoolchain go1.21.8\n",
+			},
+			want:       "1.21.8",
+			wantOrigin: "a/go.mod",
+		},
+		{
+			name: "go directive of go.work",
+			files: map[string]string{
+				"go.work":  "go 1.23.0\n\nuse ./a\n",
+				"a/go.mod": "module a\n\ngo 1.22.0\n",
+			},
+			want:       "1.23.0",
+			wantOrigin: "go.work",
+		},
+		{
+			name: "missing module is skipped",
+			files: map[string]string{
+				"go.work":  "use (\n\t./a\n\t./missing\n)\n",
+				"a/go.mod": "module a\n\ngo 1.22.0\n",
+			},
+			want:       "1.22.0",
+			wantOrigin: "a/go.mod",
+		},
+		{
+			name:    "no version anywhere",
+			files:   map[string]string{"go.work": "use ./missing\n"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, _ := newTestManager(t)
+
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			goWorkPath := filepath.Join(dir, "go.work")
+
+			resolution, err := vm.resolveGoWork(goWorkPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveGoWork() = %s, want error", resolution.Version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveGoWork() error = %v", err)
+			}
+
+			wantOrigin := filepath.Join(dir, filepath.FromSlash(tt.wantOrigin))
+			if resolution.Version != tt.want || resolution.Origin != wantOrigin || resolution.Workspace != goWorkPath {
+				t.Errorf("resolveGoWork() = %s (%s, workspace %s), want %s (%s)",
+					resolution.Version, resolution.Origin, resolution.Workspace, tt.want, wantOrigin)
+			}
+		})
+	}
+}
+
+func TestFindEnclosingGoWorkFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":       "use ./m\n",
+		"m/go.mod":      "module m\n",
+		"other/go.work": "use .\n",
+	})
+	moduleDir := filepath.Join(dir, "m", "sub")
+	writeFiles(t, moduleDir, map[string]string{"file": ""})
+
+	tests := []struct {
+		name   string
+		goWork string
+		want   string
+		wantOK bool
+	}{
+		{name: "closest parent", want: filepath.Join(dir, "go.work"), wantOK: true},
+		{name: "GOWORK file", goWork: filepath.Join(dir, "other", "go.work"), want: filepath.Join(dir, "other", "go.work"), wantOK: true},
+		{name: "GOWORK off", goWork: constants.GoWorkOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, cfg := newTestManager(t)
+			cfg.GoWork = tt.goWork
+
+			got, ok := vm.findEnclosingGoWorkFile(moduleDir)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("findEnclosingGoWorkFile() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
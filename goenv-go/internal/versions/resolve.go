@@ -0,0 +1,342 @@
+package versions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+	"github.com/go-nv/goenv/internal/hooks"
+)
+
+// OriginEnv is the origin reported when the version comes from GOENV_VERSION.
+const OriginEnv = constants.EnvGoenvVersion + " environment variable"
+
+// Resolution is a resolved Go version together with where it was set.
+type Resolution struct {
+	Version string
+	Origin  string
+
+	// Workspace is the go.work file the version was derived from, if any.
+	// Origin is then either the go.work file itself or the go.mod of the
+	// workspace module with the highest requirement.
+	Workspace string
+}
+
+// Resolve determines the Go version in effect for dir, following the same
+// order as the bash goenv-version-name: GOENV_VERSION, then `.go-version`
+// or `.tool-versions` (or `go.work` and `go.mod` when GOENV_GOMOD_VERSION_ENABLE=1) in dir and
+// each parent directory, then the global version file. An empty dir starts the search
+// at GOENV_DIR, falling back to the current directory.
+func (vm *VersionManager) Resolve(dir string) (*Resolution, error) {
+	resolution, err := vm.resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.runResolveHooks(resolution)
+}
+
+func (vm *VersionManager) resolve(dir string) (*Resolution, error) {
+	if vm.cfg.Version != "" {
+		return &Resolution{Version: vm.cfg.Version, Origin: OriginEnv}, nil
+	}
+
+	versionFilePath, err := vm.FindVersionFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	vm.cfg.Debugf("version file: %s", versionFilePath)
+
+	if isGoWorkFile(versionFilePath) {
+		return vm.resolveGoWork(versionFilePath)
+	}
+
+	version, err := vm.ReadVersionFile(versionFilePath)
+	if err != nil {
+		if vm.isGlobalVersionFile(versionFilePath) {
+			// A missing or empty global version file means the system version.
+			return &Resolution{Version: constants.GoSystemVersion, Origin: versionFilePath}, nil
+		}
+		return nil, err
+	}
+
+	return &Resolution{Version: version, Origin: versionFilePath}, nil
+}
+
+// runResolveHooks runs the version-name hooks, which may change the version
+// through GOENV_VERSION, and the version-origin hooks, which may explain it
+// through GOENV_VERSION_ORIGIN.
+func (vm *VersionManager) runResolveHooks(resolution *Resolution) (*Resolution, error) {
+	versionFile := ""
+	if resolution.Origin != OriginEnv {
+		versionFile = resolution.Origin
+	}
+
+	result, err := vm.hooks.Run(hooks.VersionName,
+		constants.EnvGoenvVersion+"="+resolution.Version,
+		hooks.EnvVersionFile+"="+versionFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if version, ok := result.Lookup(constants.EnvGoenvVersion); ok && version != resolution.Version {
+		resolution = &Resolution{Version: version, Origin: resolution.Origin}
+		if version == "" {
+			resolution.Version = constants.GoSystemVersion
+		}
+	}
+
+	result, err = vm.hooks.Run(hooks.VersionOrigin,
+		constants.EnvGoenvVersion+"="+resolution.Version,
+		hooks.EnvVersionFile+"="+versionFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if origin, ok := result.Lookup(hooks.EnvVersionOrigin); ok && origin != "" {
+		resolution.Origin = origin
+	}
+
+	return resolution, nil
+}
+
+// FindVersionFile returns the file that sets the version for dir. It returns
+// the closest local version file, or the global version file if none exists.
+// An empty dir starts the search at GOENV_DIR, falling back to the current
+// directory.
+func (vm *VersionManager) FindVersionFile(dir string) (string, error) {
+	if dir != "" {
+		if versionFilePath, ok := vm.FindLocalVersionFile(dir); ok {
+			return versionFilePath, nil
+		}
+		return vm.findGlobalVersionFile(), nil
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if vm.cfg.Dir != "" {
+		if versionFilePath, ok := vm.FindLocalVersionFile(vm.cfg.Dir); ok {
+			return versionFilePath, nil
+		}
+	}
+
+	if versionFilePath, ok := vm.FindLocalVersionFile(currentDir); ok {
+		return versionFilePath, nil
+	}
+
+	return vm.findGlobalVersionFile(), nil
+}
+
+// FindLocalVersionFile walks from dir up to the filesystem root looking for a
+// local version file and returns the closest one, if any.
+func (vm *VersionManager) FindLocalVersionFile(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		for _, name := range vm.localVersionFileNames() {
+			versionFilePath := filepath.Join(dir, name)
+			if !isRegularFile(versionFilePath) {
+				continue
+			}
+			if isToolVersionsFile(versionFilePath) && !vm.hasToolVersion(versionFilePath) {
+				continue
+			}
+			return versionFilePath, true
+		}
+
+		if vm.cfg.GoModVersionEnable {
+			if goWorkPath, ok := vm.findGoWorkFile(dir); ok {
+				return goWorkPath, true
+			}
+
+			goModPath := filepath.Join(dir, constants.GoModFile)
+			if isRegularFile(goModPath) {
+				// The enclosing workspace, if any, governs the module
+				if goWorkPath, ok := vm.findEnclosingGoWorkFile(dir); ok {
+					return goWorkPath, true
+				}
+				return goModPath, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// findGlobalVersionFile returns the first existing global version file,
+// checking the legacy locations after the current one.
+func (vm *VersionManager) findGlobalVersionFile() string {
+	for _, versionFilePath := range []string{
+		vm.globalVersionFile,
+		vm.legacyGlobalVersionFile,
+		vm.legacyDefaultVersionFile,
+	} {
+		if isRegularFile(versionFilePath) {
+			return versionFilePath
+		}
+	}
+
+	return vm.globalVersionFile
+}
+
+// isGlobalVersionFile reports whether path is one of the global version
+// files.
+func (vm *VersionManager) isGlobalVersionFile(path string) bool {
+	return path == vm.globalVersionFile || path == vm.legacyGlobalVersionFile || path == vm.legacyDefaultVersionFile
+}
+
+// ReadVersionFile reads the version from a `.go-version`, `.tool-versions`,
+// global, `go.mod` or `go.work` file.
+func (vm *VersionManager) ReadVersionFile(versionFilePath string) (string, error) {
+	if isGoModFile(versionFilePath) {
+		return vm.readGoModVersion(versionFilePath)
+	}
+
+	if isToolVersionsFile(versionFilePath) {
+		return vm.readToolVersionsVersion(versionFilePath)
+	}
+
+	if isGoWorkFile(versionFilePath) {
+		resolution, err := vm.resolveGoWork(versionFilePath)
+		if err != nil {
+			return "", err
+		}
+		return resolution.Version, nil
+	}
+
+	return vm.readVersionFile(versionFilePath)
+}
+
+// isRegularFile reports whether path exists and is a regular file.
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// InstalledVersion returns the installed version directory name for a
+// resolution. Versions read from go.mod or go.work are minimum requirements and resolve
+// to the newest installed release of the same minor line.
+func (vm *VersionManager) InstalledVersion(resolution *Resolution) (string, error) {
+	if resolution.Workspace != "" || isGoModFile(resolution.Origin) {
+		return vm.installedGoModVersion(resolution.Version)
+	}
+
+	return vm.InstalledVersionName(resolution.Version)
+}
+
+// InstalledVersionName returns the installed version directory name for
+// version, accepting an optional `go-` prefix like the bash
+// goenv-version-name, an alias, a partial version such as `1.22` which
+// selects the newest installed match, or a constraint such as `>=1.21 <1.23`
+// which selects the newest installed version satisfying it. The system
+// version is always considered installed. Like GOENV_VERSION in the bash
+// goenv, version may list several versions separated by colons; all of them
+// must be installed and their names are joined the same way.
+func (vm *VersionManager) InstalledVersionName(version string) (string, error) {
+	if !strings.Contains(version, ":") {
+		return vm.installedVersionName(version)
+	}
+
+	var names []string
+	var errs []error
+	for _, v := range strings.Split(version, ":") {
+		if v == "" {
+			continue
+		}
+		name, err := vm.installedVersionName(v)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	if len(names) == 0 {
+		return constants.GoSystemVersion, nil
+	}
+	return strings.Join(names, ":"), nil
+}
+
+// installedVersionName returns the installed version directory name for a
+// single version, as described on InstalledVersionName.
+func (vm *VersionManager) installedVersionName(version string) (string, error) {
+	if version == constants.GoSystemVersion {
+		return version, nil
+	}
+
+	if goversion.IsConstraint(version) {
+		return vm.installedConstraintVersion(version)
+	}
+
+	if vm.IsVersionInstalled(version) {
+		return version, nil
+	}
+
+	if trimmed := strings.TrimPrefix(version, "go-"); trimmed != version && vm.IsVersionInstalled(trimmed) {
+		return trimmed, nil
+	}
+
+	if target, ok := vm.ResolveAlias(version); ok {
+		if target == constants.GoSystemVersion || vm.IsVersionInstalled(target) {
+			return target, nil
+		}
+		return "", fmt.Errorf("version '%s' (alias %s) is %w", target, version, ErrNotInstalled)
+	}
+
+	// Like the bash goenv-version-name, `1.22` selects the newest installed
+	// 1.22 release
+	partial := strings.TrimPrefix(version, "go-")
+	if v, err := goversion.Parse(partial); err == nil && v.IsPartial() {
+		if latest, ok := vm.LatestInstalledVersion(partial); ok {
+			return latest, nil
+		}
+	}
+
+	return "", fmt.Errorf("version '%s' is %w", version, ErrNotInstalled)
+}
+
+// LatestInstalledVersion returns the newest installed version matching spec,
+// which may be `latest`, a partial version such as `1.22`, a constraint or an
+// exact version.
+func (vm *VersionManager) LatestInstalledVersion(spec string) (string, bool) {
+	installed, err := vm.ListVersions()
+	if err != nil {
+		return "", false
+	}
+
+	if goversion.IsConstraint(spec) {
+		constraint, err := goversion.ParseConstraint(spec)
+		if err != nil {
+			return "", false
+		}
+		return goversion.LatestSatisfying(installed, constraint)
+	}
+
+	if spec == constants.LatestVersion {
+		spec = "1"
+	}
+
+	want, err := goversion.Parse(spec)
+	if err != nil {
+		return "", false
+	}
+
+	return goversion.Latest(installed, want)
+}
@@ -0,0 +1,287 @@
+package versions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/config"
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+// newTestManager returns a VersionManager rooted in a temporary directory,
+// with the given versions installed.
+func newTestManager(t *testing.T, installed ...string) (*VersionManager, *config.Config) {
+	t.Helper()
+
+	cfg := &config.Config{RootDir: t.TempDir()}
+	for _, version := range installed {
+		if err := os.MkdirAll(filepath.Join(cfg.VersionsDir(), version, constants.VersionsBinDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vm, err := NewVersionManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vm, cfg
+}
+
+// writeFiles creates files relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string // Relative to the project directory
+		global     string
+		env        string
+		goMod      bool
+		toolFirst  bool
+		dir        string // Relative to the project directory
+		want       string
+		wantOrigin string // Relative to the project directory, or "global"
+	}{
+		{
+			name:       "environment wins over files",
+			files:      map[string]string{".go-version": "1.21.0\n"},
+			global:     "1.20.0",
+			env:        "1.22.0",
+			want:       "1.22.0",
+			wantOrigin: OriginEnv,
+		},
+		{
+			name:       "go-version in the directory",
+			files:      map[string]string{".go-version": "1.21.0\n"},
+			global:     "1.20.0",
+			want:       "1.21.0",
+			wantOrigin: ".go-version",
+		},
+		{
+			name:       "go-version in a parent directory",
+			files:      map[string]string{".go-version": "1.21.0\n", "a/b/file": ""},
+			dir:        "a/b",
+			want:       "1.21.0",
+			wantOrigin: ".go-version",
+		},
+		{
+			name:       "closest file wins",
+			files:      map[string]string{".go-version": "1.21.0\n", "a/.go-version": "1.22.0\n"},
+			dir:        "a",
+			want:       "1.22.0",
+			wantOrigin: "a/.go-version",
+		},
+		{
+			name:       "go-version before tool-versions",
+			files:      map[string]string{".go-version": "1.21.0\n", ".tool-versions": "golang 1.22.0\n"},
+			want:       "1.21.0",
+			wantOrigin: ".go-version",
+		},
+		{
+			name:       "tool-versions first",
+			files:      map[string]string{".go-version": "1.21.0\n", ".tool-versions": "golang 1.22.0\n"},
+			toolFirst:  true,
+			want:       "1.22.0",
+			wantOrigin: ".tool-versions",
+		},
+		{
+			name:       "tool-versions without golang is skipped",
+			files:      map[string]string{".tool-versions": "nodejs 20.0.0\n"},
+			global:     "1.20.0",
+			want:       "1.20.0",
+			wantOrigin: "global",
+		},
+		{
+			name:       "go.mod ignored by default",
+			files:      map[string]string{"go.mod": "module m\n\ngo 1.22.0\n"},
+			global:     "1.20.0",
+			want:       "1.20.0",
+			wantOrigin: "global",
+		},
+		{
+			name:       "go.mod when enabled",
+			files:      map[string]string{"go.mod": "module m\n\ngo 1.22.0\n"},
+			goMod:      true,
+			want:       "1.22.0",
+			wantOrigin: "go.mod",
+		},
+		{
+			name:       "go-version before go.mod",
+			files:      map[string]string{".go-version": "1.21.0\n", "go.mod": "module m\n\ngo 1.22.0\n"},
+			goMod:      true,
+			want:       "1.21.0",
+			wantOrigin: ".go-version",
+		},
+		{
+			name: "enclosing go.work governs go.mod",
+			files: map[string]string{
+				"go.work":  "go 1.21.0\n\nuse ./m\n",
+				"m/go.mod": "module m\n\ngo 1.22.0\n",
+			},
+			goMod:      true,
+			dir:        "m",
+			want:       "1.22.0",
+			wantOrigin: "m/go.mod",
+		},
+		{
+			name:       "closer go-version before parent go.mod",
+			files:      map[string]string{"go.mod": "module m\n\ngo 1.22.0\n", "a/.go-version": "1.21.0\n"},
+			goMod:      true,
+			dir:        "a",
+			want:       "1.21.0",
+			wantOrigin: "a/.go-version",
+		},
+		{
+			name:       "global version",
+			global:     "1.20.0",
+			want:       "1.20.0",
+			wantOrigin: "global",
+		},
+		{
+			name:       "system without any version file",
+			want:       constants.GoSystemVersion,
+			wantOrigin: "global",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, cfg := newTestManager(t)
+			cfg.Version = tt.env
+			cfg.GoModVersionEnable = tt.goMod
+			if tt.toolFirst {
+				cfg.ToolVersions = constants.ToolVersionsFirst
+			}
+
+			project := t.TempDir()
+			writeFiles(t, project, tt.files)
+			if tt.global != "" {
+				writeFiles(t, cfg.RootDir, map[string]string{constants.GlobalGoVersionFile: tt.global + "\n"})
+			}
+
+			resolution, err := vm.Resolve(filepath.Join(project, filepath.FromSlash(tt.dir)))
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			wantOrigin := tt.wantOrigin
+			switch wantOrigin {
+			case OriginEnv:
+			case "global":
+				wantOrigin = filepath.Join(cfg.RootDir, constants.GlobalGoVersionFile)
+			default:
+				wantOrigin = filepath.Join(project, filepath.FromSlash(wantOrigin))
+			}
+
+			if resolution.Version != tt.want || resolution.Origin != wantOrigin {
+				t.Errorf("Resolve() = %s (%s), want %s (%s)", resolution.Version, resolution.Origin, tt.want, wantOrigin)
+			}
+		})
+	}
+}
+
+func TestResolveGoenvDir(t *testing.T) {
+	vm, cfg := newTestManager(t)
+
+	project := t.TempDir()
+	writeFiles(t, project, map[string]string{".go-version": "1.21.0\n"})
+	cfg.Dir = project
+
+	resolution, err := vm.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolution.Version != "1.21.0" {
+		t.Errorf("Resolve() = %s, want 1.21.0 from GOENV_DIR", resolution.Version)
+	}
+}
+
+func TestInstalledVersionName(t *testing.T) {
+	vm, _ := newTestManager(t, "1.21.3", "1.22.0", "1.22.3")
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "1.22.0", want: "1.22.0"},
+		{version: "go-1.22.0", want: "1.22.0"},
+		{version: "1.22", want: "1.22.3"},
+		{version: "go-1.21", want: "1.21.3"},
+		{version: "1", want: "1.22.3"},
+		{version: ">=1.21 <1.22", want: "1.21.3"},
+		{version: "system", want: "system"},
+		{version: "1.22.3:1.21.3", want: "1.22.3:1.21.3"},
+		{version: "1.21:system", want: "1.21.3:system"},
+		{version: "1.23", wantErr: true},
+		{version: "1.22.1", wantErr: true},
+		{version: "1.22.3:1.20.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := vm.InstalledVersionName(tt.version)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotInstalled) {
+					t.Errorf("InstalledVersionName(%q) = %q, %v, want %v", tt.version, got, err, ErrNotInstalled)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InstalledVersionName(%q) error = %v", tt.version, err)
+			}
+			if got != tt.want {
+				t.Errorf("InstalledVersionName(%q) = %s, want %s", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstalledVersionPartial(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		files map[string]string
+		want  string
+	}{
+		{name: "partial go-version", files: map[string]string{".go-version": "1.22\n"}, want: "1.22.3"},
+		{name: "partial GOENV_VERSION", env: "1.22", want: "1.22.3"},
+		{name: "colon-separated GOENV_VERSION", env: "1.21:1.22.3", want: "1.21.3:1.22.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, cfg := newTestManager(t, "1.21.3", "1.22.3")
+			cfg.Version = tt.env
+
+			project := t.TempDir()
+			writeFiles(t, project, tt.files)
+
+			resolution, err := vm.Resolve(project)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			got, err := vm.InstalledVersion(resolution)
+			if err != nil {
+				t.Fatalf("InstalledVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InstalledVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
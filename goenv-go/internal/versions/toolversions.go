@@ -0,0 +1,101 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+)
+
+// toolVersionsPlugin is the asdf plugin name whose line sets the Go version
+// in a `.tool-versions` file.
+const toolVersionsPlugin = "golang"
+
+// isToolVersionsFile reports whether path is an asdf `.tool-versions` file.
+func isToolVersionsFile(path string) bool {
+	return filepath.Base(path) == constants.ToolVersionsFile
+}
+
+// localVersionFileNames returns the local version file names to look for in
+// each directory, in order of priority.
+func (vm *VersionManager) localVersionFileNames() []string {
+	switch vm.cfg.ToolVersions {
+	case constants.ToolVersionsOff:
+		return []string{constants.LocalGoVersionFile}
+	case constants.ToolVersionsFirst:
+		return []string{constants.ToolVersionsFile, constants.LocalGoVersionFile}
+	default:
+		return []string{constants.LocalGoVersionFile, constants.ToolVersionsFile}
+	}
+}
+
+// hasToolVersion reports whether the `.tool-versions` file at path sets a Go
+// version, so that files only listing other tools are skipped.
+func (vm *VersionManager) hasToolVersion(path string) bool {
+	_, err := vm.readToolVersionsVersion(path)
+	return err == nil
+}
+
+// readToolVersionsVersion reads the version of the golang line of a
+// `.tool-versions` file. A constraint such as `>=1.21 <1.23` spans all the
+// remaining fields; otherwise they list asdf fallback versions and the first
+// one is used.
+func (vm *VersionManager) readToolVersionsVersion(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != toolVersionsPlugin {
+			continue
+		}
+
+		if version := strings.Join(fields[1:], " "); goversion.IsConstraint(version) {
+			return version, nil
+		}
+		return fields[1], nil
+	}
+
+	return "", fmt.Errorf("no %s version found in %s", toolVersionsPlugin, path)
+}
+
+// writeToolVersionsVersion sets the golang line of the `.tool-versions` file
+// at path to version, preserving the lines of other tools and comments.
+func (vm *VersionManager) writeToolVersionsVersion(path, version string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	found := false
+	for i, line := range lines {
+		entry, comment, hasComment := strings.Cut(line, "#")
+		fields := strings.Fields(entry)
+		if len(fields) == 0 || fields[0] != toolVersionsPlugin || found {
+			continue
+		}
+
+		lines[i] = toolVersionsPlugin + " " + version
+		if hasComment {
+			lines[i] += " #" + comment
+		}
+		found = true
+	}
+
+	if !found {
+		lines = append(lines, toolVersionsPlugin+" "+version)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,168 @@
+package versions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-nv/goenv/internal/constants"
+)
+
+func TestToolVersionsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // Empty for a missing file
+		version string
+		want    string
+	}{
+		{
+			name:    "new file",
+			version: "1.22.0",
+			want:    "golang 1.22.0\n",
+		},
+		{
+			name:    "other tools are kept",
+			content: "nodejs 20.11.0\n# pinned for CI\npython 3.12.1\n",
+			version: "1.22.0",
+			want:    "nodejs 20.11.0\n# pinned for CI\npython 3.12.1\ngolang 1.22.0\n",
+		},
+		{
+			name:    "golang line is replaced in place",
+			content: "nodejs 20.11.0\ngolang 1.21.0\npython 3.12.1\n",
+			version: "1.22.0",
+			want:    "nodejs 20.11.0\ngolang 1.22.0\npython 3.12.1\n",
+		},
+		{
+			name:    "comment on the golang line is kept",
+			content: "golang 1.21.0 # matches go.mod\nnodejs 20.11.0",
+			version: "1.22.0",
+			want:    "golang 1.22.0 # matches go.mod\nnodejs 20.11.0\n",
+		},
+		{
+			name:    "only the first golang line is replaced",
+			content: "golang 1.21.0\ngolang 1.20.0\n",
+			version: "1.22.0",
+			want:    "golang 1.22.0\ngolang 1.20.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, _ := newTestManager(t)
+
+			path := filepath.Join(t.TempDir(), ".tool-versions")
+			if tt.content != "" {
+				writeFiles(t, filepath.Dir(path), map[string]string{".tool-versions": tt.content})
+			}
+
+			if err := vm.writeToolVersionsVersion(path, tt.version); err != nil {
+				t.Fatalf("writeToolVersionsVersion() error = %v", err)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("file content = %q, want %q", content, tt.want)
+			}
+
+			got, err := vm.readToolVersionsVersion(path)
+			if err != nil {
+				t.Fatalf("readToolVersionsVersion() error = %v", err)
+			}
+			if got != tt.version {
+				t.Errorf("readToolVersionsVersion() = %s, want %s", got, tt.version)
+			}
+		})
+	}
+}
+
+func TestReadToolVersionsVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "golang line", content: "nodejs 20.11.0\ngolang 1.22.0\n", want: "1.22.0"},
+		{name: "comment after the version", content: "golang 1.22.0 # pinned\n", want: "1.22.0"},
+		{name: "constraint spans the remaining fields", content: "golang >= 1.21 <1.23 # pinned\n", want: ">= 1.21 <1.23"},
+		{name: "fallback versions", content: "golang 1.22.0 1.21.0\n", want: "1.22.0"},
+		{name: "commented out", content: "# golang 1.22.0\n", wantErr: true},
+		{name: "no golang line", content: "nodejs 20.11.0\n", wantErr: true},
+		{name: "golang without version", content: "golang\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm, _ := newTestManager(t)
+
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{".tool-versions": tt.content})
+
+			got, err := vm.readToolVersionsVersion(filepath.Join(dir, ".tool-versions"))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readToolVersionsVersion() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readToolVersionsVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readToolVersionsVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLocalVersionToolVersionsAlias(t *testing.T) {
+	vm, _ := newTestManager(t, "1.22.0")
+	if _, err := vm.SetAlias("stable", "1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	version, err := vm.SetLocalVersion("stable", constants.FormatToolVersions)
+	if err != nil {
+		t.Fatalf("SetLocalVersion() error = %v", err)
+	}
+	if version != "1.22.0" {
+		t.Errorf("SetLocalVersion() = %s, want 1.22.0", version)
+	}
+
+	got, err := vm.readToolVersionsVersion(filepath.Join(dir, constants.ToolVersionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1.22.0" {
+		t.Errorf(".tool-versions holds %s, want 1.22.0", got)
+	}
+}
+
+func TestSetLocalVersionToolVersionsConstraint(t *testing.T) {
+	vm, _ := newTestManager(t, "1.21.5", "1.22.0", "1.23.1")
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	version, err := vm.SetLocalVersion(">=1.21 <1.23", constants.FormatToolVersions)
+	if err != nil {
+		t.Fatalf("SetLocalVersion() error = %v", err)
+	}
+	if version != "1.22.0" {
+		t.Errorf("SetLocalVersion() = %s, want 1.22.0", version)
+	}
+
+	got, err := vm.readToolVersionsVersion(filepath.Join(dir, constants.ToolVersionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1.22.0" {
+		t.Errorf(".tool-versions holds %s, want 1.22.0", got)
+	}
+}
@@ -1,18 +1,27 @@
 package versions
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/go-nv/goenv/internal/config"
 	"github.com/go-nv/goenv/internal/constants"
+	"github.com/go-nv/goenv/internal/goversion"
+	"github.com/go-nv/goenv/internal/hooks"
 	"github.com/go-nv/goenv/internal/installer"
-	"github.com/go-nv/goenv/internal/utils"
 )
 
+// ErrNotInstalled is returned when the selected or requested version is not
+// installed.
+var ErrNotInstalled = errors.New("not installed")
+
 // VersionManager handles Go version management.
 type VersionManager struct {
+	cfg                      *config.Config
+	hooks                    *hooks.HookManager
 	rootDir                  string
 	versionsDir              string
 	globalVersionFile        string
@@ -21,15 +30,19 @@ type VersionManager struct {
 }
 
 // NewVersionManager creates a new VersionManager instance.
-func NewVersionManager() (*VersionManager, error) {
-	rootDir, err := utils.GetGoenvRootDir()
+func NewVersionManager(cfg *config.Config) (*VersionManager, error) {
+	rootDir := cfg.RootDir
+
+	hm, err := hooks.NewHookManager(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, err
 	}
 
 	return &VersionManager{
+		cfg:                      cfg,
+		hooks:                    hm,
 		rootDir:                  rootDir,
-		versionsDir:              filepath.Join(rootDir, constants.VersionsDir),
+		versionsDir:              cfg.VersionsDir(),
 		globalVersionFile:        filepath.Join(rootDir, constants.GlobalGoVersionFile),
 		legacyDefaultVersionFile: filepath.Join(rootDir, constants.GlobalVersionFileLegacyDefault),
 		legacyGlobalVersionFile:  filepath.Join(rootDir, constants.GlobalVersionFileLegacyGlobal),
@@ -70,48 +83,151 @@ func (vm *VersionManager) GetLocalVersionFile() (string, error) {
 	return filepath.Join(currentDir, constants.LocalGoVersionFile), nil
 }
 
-// GetLocalVersion returns the local version.
+// GetLocalVersion returns the local version, looking in the current
+// directory and each of its parents.
 func (vm *VersionManager) GetLocalVersion() (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	versionFilePath, ok := vm.FindLocalVersionFile(currentDir)
+	if !ok {
+		return "", fmt.Errorf("No local version found in %s", currentDir)
+	}
+
+	return vm.ReadVersionFile(versionFilePath)
+}
+
+// SetLocalVersion sets the local version in the current directory and returns
+// the version written, after resolving partial versions and aliases such as
+// `latest`. The format selects between a `.go-version` file and the golang
+// line of an asdf `.tool-versions` file.
+func (vm *VersionManager) SetLocalVersion(version, format string) (string, error) {
 	versionFilePath, err := vm.GetLocalVersionFile()
 	if err != nil {
 		return "", fmt.Errorf("failed to get local version file: %w", err)
 	}
-	if _, err := os.Stat(versionFilePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("No local version found in %s", currentDir)
+
+	switch format {
+	case "", constants.FormatGoVersion:
+	case constants.FormatToolVersions:
+		versionFilePath = filepath.Join(filepath.Dir(versionFilePath), constants.ToolVersionsFile)
+	default:
+		return "", fmt.Errorf("unknown version file format %q", format)
 	}
 
-	return vm.readVersionFile(versionFilePath)
+	version, err = vm.ensureInstalled(version)
+	if err != nil {
+		return "", err
+	}
+
+	// asdf knows nothing about goenv aliases or constraints, so
+	// .tool-versions records the version the alias points at and the newest
+	// installed version satisfying a constraint
+	if format == constants.FormatToolVersions {
+		if target, ok := vm.ResolveAlias(version); ok {
+			version = target
+		} else if goversion.IsConstraint(version) {
+			if version, err = vm.installedConstraintVersion(version); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// write the version to the local version file
+	if format == constants.FormatToolVersions {
+		err = vm.writeToolVersionsVersion(versionFilePath, version)
+	} else {
+		err = vm.writeVersionFile(versionFilePath, version)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write version file: %w", err)
+	}
+
+	return version, nil
 }
 
-// SetLocalVersion sets the local version.
-func (vm *VersionManager) SetLocalVersion(version string) error {
-	versionFilePath, err := vm.GetLocalVersionFile()
+// ensureInstalled makes sure version, which may be an alias, is installed
+// and returns the version to record.
+func (vm *VersionManager) ensureInstalled(version string) (string, error) {
+	if vm.IsVersionInstalled(version) {
+		return version, nil
+	}
+
+	// Aliases are kept as is so that updating the alias updates every user
+	if target, ok := vm.ResolveAlias(version); ok {
+		if target != constants.GoSystemVersion && !vm.IsVersionInstalled(target) {
+			if _, err := vm.installVersion(target); err != nil {
+				return "", err
+			}
+		}
+		return version, nil
+	}
+
+	// Constraints are kept as is too, so that the newest matching version
+	// installed later is picked up
+	if goversion.IsConstraint(version) {
+		if _, err := vm.installVersion(version); err != nil {
+			return "", err
+		}
+		return version, nil
+	}
+
+	return vm.installVersion(version)
+}
+
+// installVersion resolves version like resolveVersion, installs it if
+// needed and returns the resolved version.
+func (vm *VersionManager) installVersion(version string) (string, error) {
+	if goversion.IsConstraint(version) {
+		if installed, ok := vm.LatestInstalledVersion(version); ok {
+			return installed, nil
+		}
+	}
+
+	installer, err := installer.NewInstaller(vm.cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get local version file: %w", err)
+		return "", fmt.Errorf("failed to create installer: %w", err)
+	}
+
+	release, err := installer.Resolve(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve version: %w", err)
 	}
 
 	// if the version is not installed, install it
-	if !vm.IsVersionInstalled(version) {
-		installer, err := installer.NewInstaller()
-		if err != nil {
-			return fmt.Errorf("failed to create installer: %w", err)
+	if !vm.IsVersionInstalled(release.Version) {
+		if err := installer.Install(release); err != nil {
+			return "", fmt.Errorf("failed to install version: %w", err)
 		}
-		if err := installer.Install(version); err != nil {
-			return fmt.Errorf("failed to install version: %w", err)
+	}
+
+	return release.Version, nil
+}
+
+// resolveVersion resolves a version specifier to a release. Constraints
+// select the newest installed version satisfying them, if any; partial
+// versions, `latest` and everything else are resolved against the release
+// index so that they always get the newest release.
+func (vm *VersionManager) resolveVersion(version string) (string, error) {
+	if goversion.IsConstraint(version) {
+		if installed, ok := vm.LatestInstalledVersion(version); ok {
+			return installed, nil
 		}
 	}
 
-	// write the version to the local version file
-	if err := vm.writeVersionFile(versionFilePath, version); err != nil {
-		return fmt.Errorf("failed to write version file: %w", err)
+	installer, err := installer.NewInstaller(vm.cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create installer: %w", err)
 	}
 
-	return nil
+	resolved, err := installer.ResolveVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve version: %w", err)
+	}
+
+	return resolved, nil
 }
 
 // UnsetLocalVersion unsets the local version.
@@ -127,19 +243,31 @@ func (vm *VersionManager) UnsetLocalVersion() error {
 	return nil
 }
 
-// readVersionFile reads the version file.
+// readVersionFile reads the first non-comment word of the version file, or
+// the whole line if it holds a version constraint such as `>=1.21 <1.23`.
 func (vm *VersionManager) readVersionFile(versionFilePath string) (string, error) {
 	content, err := os.ReadFile(versionFilePath)
 	if err != nil {
 		return "", err
 	}
 
-	return strings.TrimSpace(string(content)), nil
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if line, _, _ = strings.Cut(line, "#"); goversion.IsConstraint(line) {
+			return strings.Join(strings.Fields(line), " "), nil
+		}
+		return fields[0], nil
+	}
+
+	return "", fmt.Errorf("no version found in %s", versionFilePath)
 }
 
 // writeVersionFile writes the version file.
 func (vm *VersionManager) writeVersionFile(versionFilePath string, version string) error {
-	if err := os.WriteFile(versionFilePath, []byte(version), 0644); err != nil {
+	if err := os.WriteFile(versionFilePath, []byte(version+"\n"), 0644); err != nil {
 		return err
 	}
 
@@ -159,72 +287,55 @@ func (vm *VersionManager) rmVersionFile(versionFilePath string) error {
 
 // GetGlobalVersion returns the global version.
 func (vm *VersionManager) GetGlobalVersion() (string, error) {
-	versionFilePath := vm.GetGlobalVersionFile()
-	legacyDefaultVersionFilePath := vm.GetLegacyGlobalVersionFile()
-	legacyGlobalVersionFilePath := vm.GetLegacyDefaultVersionFile()
-
-	if _, err := os.Stat(versionFilePath); err == nil {
-		return vm.readVersionFile(versionFilePath)
+	versionFilePath := vm.findGlobalVersionFile()
+	if !isRegularFile(versionFilePath) {
+		return constants.GoSystemVersion, nil
 	}
 
-	if _, err := os.Stat(legacyDefaultVersionFilePath); err == nil {
-		return vm.readVersionFile(legacyDefaultVersionFilePath)
-	}
-
-	if _, err := os.Stat(legacyGlobalVersionFilePath); err == nil {
-		return vm.readVersionFile(legacyGlobalVersionFilePath)
-	}
-
-	return constants.GoSystemVersion, nil
+	return vm.readVersionFile(versionFilePath)
 }
 
-// SetGlobalVersion sets the global version.
-func (vm *VersionManager) SetGlobalVersion(version string) error {
+// SetGlobalVersion sets the global version and returns the version written,
+// after resolving partial versions and aliases such as `latest`.
+func (vm *VersionManager) SetGlobalVersion(version string) (string, error) {
 	// if the version is the system version, remove the global version file
 	if version == constants.GoSystemVersion {
 		var currentGlobalVersion string
 
-		if vm.versionFileExists(filepath.Join(vm.rootDir, constants.GlobalGoVersionFile)) {
+		if vm.versionFileExists(vm.globalVersionFile) {
 			var err error
 			currentGlobalVersion, err = vm.GetGlobalVersion()
 			if err != nil {
-				return fmt.Errorf("failed to get current global version: %w", err)
+				return "", fmt.Errorf("failed to get current global version: %w", err)
 			}
-		}
 
-		if err := vm.rmVersionFile(filepath.Join(vm.rootDir, constants.GlobalGoVersionFile)); err != nil {
-			return fmt.Errorf("failed to remove global version file: %w", err)
+			if err := vm.rmVersionFile(vm.globalVersionFile); err != nil {
+				return "", fmt.Errorf("failed to remove global version file: %w", err)
+			}
 		}
 
 		if currentGlobalVersion != "" {
 			fmt.Printf("using system version instead of %s now\n", currentGlobalVersion)
 		}
-		return nil
+		return version, nil
 	}
 
-	// if the version is not installed, install it
-	if !vm.IsVersionInstalled(version) {
-		installer, err := installer.NewInstaller()
-		if err != nil {
-			return fmt.Errorf("failed to create installer: %w", err)
-		}
-		if err := installer.Install(version); err != nil {
-			return fmt.Errorf("failed to install version: %w", err)
-		}
+	version, err := vm.ensureInstalled(version)
+	if err != nil {
+		return "", err
 	}
 
 	// write the version to the global version file
-	versionFilePath := filepath.Join(vm.rootDir, constants.GlobalGoVersionFile)
-	if err := os.WriteFile(versionFilePath, []byte(version), 0644); err != nil {
-		return fmt.Errorf("failed to write version file: %w", err)
+	if err := vm.writeVersionFile(vm.globalVersionFile, version); err != nil {
+		return "", fmt.Errorf("failed to write version file: %w", err)
 	}
 
-	return nil
+	return version, nil
 }
 
-// ListVersions lists all versions.
+// ListVersions lists all installed versions, oldest first.
 func (vm *VersionManager) ListVersions() ([]string, error) {
-	versionsDir := filepath.Join(vm.rootDir, constants.VersionsDir)
+	versionsDir := vm.versionsDir
 	if _, err := os.Stat(versionsDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("No versions found in %s", versionsDir)
 	}
@@ -240,6 +351,7 @@ func (vm *VersionManager) ListVersions() ([]string, error) {
 			versions = append(versions, file.Name())
 		}
 	}
+	goversion.Sort(versions)
 
 	return versions, nil
 }